@@ -0,0 +1,378 @@
+package certchecker
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// caaType CAAレコードのタイプ（RFC 8659）。dnsmessageには定義されていないため番号で指定する
+const caaType dnsmessage.Type = 257
+
+// caaRecord CAAレコード1件の内容
+type caaRecord struct {
+	Flag  uint8
+	Tag   string
+	Value string
+}
+
+// String dig などと同じ形式（0 issue "letsencrypt.org"）で表示する
+func (r caaRecord) String() string {
+	return fmt.Sprintf("%d %s %q", r.Flag, r.Tag, r.Value)
+}
+
+// caaIssuerIdentifiers 証明書の発行者名（組織名）に含まれる語と、そのCAがCAAレコードで使用する識別子
+// CAAレコードにはCAのドメイン名を記載するため、証明書の発行者名から対応する識別子を求める
+var caaIssuerIdentifiers = []struct {
+	keyword     string
+	identifiers []string
+}{
+	{"let's encrypt", []string{"letsencrypt.org"}},
+	{"google trust services", []string{"pki.goog"}},
+	{"digicert", []string{"digicert.com", "symantec.com", "geotrust.com", "rapidssl.com", "thawte.com", "digitalcertvalidation.com"}},
+	{"sectigo", []string{"sectigo.com", "comodoca.com", "comodo.com", "usertrust.com", "trust-provider.com"}},
+	{"comodo", []string{"sectigo.com", "comodoca.com", "comodo.com", "usertrust.com", "trust-provider.com"}},
+	{"zerossl", []string{"sectigo.com", "zerossl.com"}},
+	{"globalsign", []string{"globalsign.com"}},
+	{"amazon", []string{"amazon.com", "amazontrust.com", "awstrust.com", "amazonaws.com"}},
+	{"entrust", []string{"entrust.net", "affirmtrust.com"}},
+	{"godaddy", []string{"godaddy.com", "starfieldtech.com"}},
+	{"starfield", []string{"godaddy.com", "starfieldtech.com"}},
+	{"buypass", []string{"buypass.com", "buypass.no"}},
+	{"ssl corporation", []string{"ssl.com"}},
+	{"microsoft", []string{"microsoft.com"}},
+	{"certum", []string{"certum.pl", "certum.eu"}},
+	{"actalis", []string{"actalis.it"}},
+	{"harica", []string{"harica.gr"}},
+}
+
+// caaIdentifiers 証明書の発行者名に対応するCAAの識別子を返す（不明な場合はnil）
+func caaIdentifiers(issuer string) []string {
+	issuer = strings.ToLower(issuer)
+	for _, ca := range caaIssuerIdentifiers {
+		if strings.Contains(issuer, ca.keyword) {
+			return ca.identifiers
+		}
+	}
+	return nil
+}
+
+// caaAuthorized CAAレコードで許可されたCAの識別子を返す
+// restrictedがfalseの場合は発行を制限するレコード（issue/issuewild）がなく、どのCAでも発行できる
+// ワイルドカード証明書は、issuewildがあればそちらを優先する（RFC 8659 4.3）
+func caaAuthorized(records []caaRecord, wildcard bool) (allowed []string, restricted bool) {
+	tag := "issue"
+	if wildcard {
+		for _, record := range records {
+			if strings.EqualFold(record.Tag, "issuewild") {
+				tag = "issuewild"
+				break
+			}
+		}
+	}
+	for _, record := range records {
+		if !strings.EqualFold(record.Tag, tag) {
+			continue
+		}
+		restricted = true
+		// 値は「ドメイン名; パラメーター」の形式。ドメイン名が空（";"）の場合はどのCAにも発行を許可しない
+		domain, _, _ := strings.Cut(record.Value, ";")
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			allowed = append(allowed, domain)
+		}
+	}
+	return allowed, restricted
+}
+
+// caaLookup CAAレコードを取得する関数（テストでスタブに置き換える）
+var caaLookup = dnsLookupCAA
+
+// lookupRelevantCAA ホスト名から親ドメインへ順にたどり、最初に見つかったCAAレコードとそのドメインを返す（RFC 8659 3）
+// トップレベルドメインは確認しない
+func lookupRelevantCAA(ctx context.Context, config *Config, host string) ([]caaRecord, string, error) {
+	labels := strings.Split(strings.TrimSuffix(host, "."), ".")
+	for i := 0; i < len(labels)-1; i++ {
+		domain := strings.Join(labels[i:], ".")
+		records, err := caaLookup(ctx, config, domain)
+		if err != nil {
+			return nil, domain, err
+		}
+		if len(records) > 0 {
+			return records, domain, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// checkCAA CAAレコードを取得し、証明書の発行者が許可されているかを確認する
+// CAAレコードの取得に失敗した場合や、発行者に対応する識別子が不明な場合は、ステータスを変更しない
+func checkCAA(ctx context.Context, config *Config, info *CertInfo, host string, cert *x509.Certificate) {
+	if host == "" || net.ParseIP(host) != nil {
+		return
+	}
+	records, domain, err := lookupRelevantCAA(ctx, config, host)
+	if err != nil {
+		LogWarnf("%s:%d - CAAレコードの取得に失敗: %s: %v", info.URL, info.Port, domain, err)
+		return
+	}
+	if len(records) == 0 {
+		LogDebugf("%s:%d - CAAレコードがないため、どのCAでも発行できます", info.URL, info.Port)
+		return
+	}
+
+	info.CAADomain = domain
+	for _, record := range records {
+		info.CAARecords = append(info.CAARecords, record.String())
+	}
+
+	wildcard := false
+	for _, name := range cert.DNSNames {
+		if strings.HasPrefix(name, "*.") {
+			wildcard = true
+			break
+		}
+	}
+	allowed, restricted := caaAuthorized(records, wildcard)
+	if !restricted {
+		return
+	}
+	identifiers := caaIdentifiers(info.Issuer)
+	if identifiers == nil {
+		LogInfof("%s:%d - 発行者（%s）に対応するCAAの識別子が不明なため、CAAレコードによる確認をスキップします", info.URL, info.Port, info.Issuer)
+		return
+	}
+	for _, identifier := range identifiers {
+		for _, domain := range allowed {
+			if domain == identifier {
+				return
+			}
+		}
+	}
+	permitted := strings.Join(allowed, ", ")
+	if permitted == "" {
+		permitted = config.message("caa_none")
+	}
+	info.addProblem("WARNING", fmt.Sprintf(config.message("problem_caa_unauthorized"), info.Issuer, permitted))
+}
+
+// resolvConfPath ネームサーバーを読み込むファイル
+var resolvConfPath = "/etc/resolv.conf"
+
+// dnsServerAddress alert.dns_serversの指定（ポート番号は省略可）を接続先のアドレスにする
+func dnsServerAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), "53")
+}
+
+// validDNSServer alert.dns_serversの指定が「ホスト」または「ホスト:ポート」の形式か
+func validDNSServer(server string) bool {
+	host, port, err := net.SplitHostPort(dnsServerAddress(server))
+	if err != nil || host == "" {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n > 0 && n <= 65535
+}
+
+// dnsServers CAAレコードの問い合わせに使用するネームサーバーを返す
+// alert.dns_serversを指定した場合はそれを使用する。省略時はresolv.confのネームサーバー（resolv.confがないWindowsなどではローカルホスト）
+func dnsServers(config *Config) []string {
+	if len(config.Alert.DNSServers) > 0 {
+		servers := make([]string, 0, len(config.Alert.DNSServers))
+		for _, server := range config.Alert.DNSServers {
+			servers = append(servers, dnsServerAddress(server))
+		}
+		return servers
+	}
+
+	var servers []string
+	if f, err := os.Open(resolvConfPath); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "nameserver" {
+				servers = append(servers, net.JoinHostPort(fields[1], "53"))
+			}
+		}
+	}
+	if len(servers) == 0 {
+		servers = []string{"127.0.0.1:53"}
+	}
+	return servers
+}
+
+// dnsLookupCAA ネームサーバーにCAAレコードを問い合わせる
+// 標準ライブラリのリゾルバーはCAAに対応していないため、DNSメッセージを組み立てて直接問い合わせる
+func dnsLookupCAA(ctx context.Context, config *Config, domain string) ([]caaRecord, error) {
+	var lastErr error
+	for _, server := range dnsServers(config) {
+		records, err := queryCAA(ctx, config, server, domain)
+		if err == nil {
+			return records, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// dnsTimeout ネームサーバー1台への問い合わせのタイムアウト
+const dnsTimeout = 5 * time.Second
+
+// queryCAA 指定したネームサーバーにCAAレコードを問い合わせる
+// 応答が切り詰められた場合はTCPで問い合わせ直す。ドメインが存在しない場合はレコードなしとして扱う
+// alert.socks_proxyを指定した場合は、証明書の取得と同じくプロキシ経由で問い合わせる（SOCKS5プロキシはUDPを中継できないため常にTCP）
+func queryCAA(ctx context.Context, config *Config, server, domain string) ([]caaRecord, error) {
+	name, err := dnsmessage.NewName(strings.TrimSuffix(domain, ".") + ".")
+	if err != nil {
+		return nil, err
+	}
+	// 応答の偽装を防ぐため、IDには予測できない乱数を使用する
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: caaType, Class: dnsmessage.ClassINET}},
+	}
+	var opt dnsmessage.ResourceHeader
+	if err := opt.SetEDNS0(4096, dnsmessage.RCodeSuccess, false); err != nil {
+		return nil, err
+	}
+	query.Additionals = []dnsmessage.Resource{{Header: opt, Body: &dnsmessage.OPTResource{}}}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := siteDialer(config)
+	network := "udp"
+	if config.socksDialer != nil {
+		network = "tcp"
+	}
+	resp, err := exchangeDNS(ctx, dialer, network, server, packed)
+	if err != nil {
+		return nil, err
+	}
+	var parser dnsmessage.Parser
+	header, err := parser.Start(resp)
+	if err != nil {
+		return nil, fmt.Errorf("応答の解析に失敗: %v", err)
+	}
+	if header.Truncated && network == "udp" {
+		if resp, err = exchangeDNS(ctx, dialer, "tcp", server, packed); err != nil {
+			return nil, err
+		}
+		if header, err = parser.Start(resp); err != nil {
+			return nil, fmt.Errorf("応答の解析に失敗: %v", err)
+		}
+	}
+	if header.ID != id {
+		return nil, errors.New("応答のIDが一致しません")
+	}
+	switch header.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("ネームサーバーがエラーを返しました: %v", header.RCode)
+	}
+
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, fmt.Errorf("応答の解析に失敗: %v", err)
+	}
+	var records []caaRecord
+	for {
+		answer, err := parser.AnswerHeader()
+		if errors.Is(err, dnsmessage.ErrSectionDone) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("応答の解析に失敗: %v", err)
+		}
+		// CNAMEをたどった場合も、応答に含まれるCAAレコードを使用する
+		if answer.Type != caaType {
+			if err := parser.SkipAnswer(); err != nil {
+				return nil, fmt.Errorf("応答の解析に失敗: %v", err)
+			}
+			continue
+		}
+		resource, err := parser.UnknownResource()
+		if err != nil {
+			return nil, fmt.Errorf("応答の解析に失敗: %v", err)
+		}
+		record, err := parseCAARecord(resource.Data)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseCAARecord CAAレコードのRDATA（フラグ、タグの長さ、タグ、値）を解析する
+func parseCAARecord(data []byte) (caaRecord, error) {
+	if len(data) < 2 || len(data) < 2+int(data[1]) {
+		return caaRecord{}, errors.New("CAAレコードの形式が正しくありません")
+	}
+	tagLen := int(data[1])
+	return caaRecord{Flag: data[0], Tag: string(data[2 : 2+tagLen]), Value: string(data[2+tagLen:])}, nil
+}
+
+// exchangeDNS DNSメッセージを送信して応答を受け取る（TCPの場合は長さの接頭辞を付ける）
+func exchangeDNS(ctx context.Context, dialer contextDialer, network, server string, msg []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, dnsTimeout)
+	defer cancel()
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if network == "tcp" {
+		prefixed := make([]byte, 2+len(msg))
+		binary.BigEndian.PutUint16(prefixed, uint16(len(msg)))
+		copy(prefixed[2:], msg)
+		if _, err := conn.Write(prefixed); err != nil {
+			return nil, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return nil, err
+		}
+		resp := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, 4096)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp[:n], nil
+}
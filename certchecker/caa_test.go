@@ -0,0 +1,290 @@
+package certchecker
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// stubCAALookup ドメインごとに決まったCAAレコードを返すスタブのリゾルバーに置き換える
+func stubCAALookup(t *testing.T, zone map[string][]caaRecord) *[]string {
+	t.Helper()
+	var queried []string
+	original := caaLookup
+	caaLookup = func(ctx context.Context, config *Config, domain string) ([]caaRecord, error) {
+		queried = append(queried, domain)
+		return zone[domain], nil
+	}
+	t.Cleanup(func() { caaLookup = original })
+	return &queried
+}
+
+// TestCheckCAA CAAレコードによる発行者の確認のテスト
+func TestCheckCAA(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	queried := stubCAALookup(t, map[string][]caaRecord{
+		"example.com": {
+			{Tag: "issue", Value: "letsencrypt.org"},
+			{Tag: "issuewild", Value: "digicert.com; cansignhttpexchanges=yes"},
+			{Tag: "iodef", Value: "mailto:security@example.com"},
+		},
+		"sub.example.net":   {{Tag: "issue", Value: ";"}},
+		"iodef.example.org": {{Tag: "iodef", Value: "mailto:security@example.org"}},
+	})
+
+	testCases := []struct {
+		name     string
+		host     string
+		issuer   string
+		dnsNames []string
+		status   string
+		records  int
+		message  string
+	}{
+		{name: "許可された発行者（親ドメインのレコード）", host: "www.example.com", issuer: "Let's Encrypt", status: "OK", records: 3},
+		{name: "許可されていない発行者", host: "www.example.com", issuer: "Google Trust Services", status: "WARNING", records: 3, message: "許可: letsencrypt.org"},
+		{name: "ワイルドカード証明書はissuewildを使用", host: "www.example.com", issuer: "DigiCert Inc", dnsNames: []string{"*.example.com"}, status: "OK", records: 3},
+		{name: "ワイルドカード証明書でissuewildにないCA", host: "www.example.com", issuer: "Let's Encrypt", dnsNames: []string{"*.example.com"}, status: "WARNING", records: 3},
+		{name: "すべてのCAを禁止", host: "sub.example.net", issuer: "Let's Encrypt", status: "WARNING", records: 1, message: "許可: なし"},
+		{name: "発行を制限するレコードがない", host: "iodef.example.org", issuer: "Let's Encrypt", status: "OK", records: 1},
+		{name: "CAAレコードがない", host: "www.example.jp", issuer: "Let's Encrypt", status: "OK", records: 0},
+		{name: "識別子が不明な発行者", host: "www.example.com", issuer: "Internal CA", status: "OK", records: 3},
+		{name: "IPアドレス", host: "192.0.2.1", issuer: "Let's Encrypt", status: "OK", records: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			info := CertInfo{URL: tc.host, Port: 443, Issuer: tc.issuer, Status: "OK"}
+			checkCAA(context.Background(), &Config{}, &info, tc.host, &x509.Certificate{DNSNames: tc.dnsNames})
+			if info.Status != tc.status {
+				t.Errorf("ステータス 期待: %s, 実際: %s（%s）", tc.status, info.Status, info.ErrorMessage)
+			}
+			if len(info.CAARecords) != tc.records {
+				t.Errorf("CAAレコードの数 期待: %d, 実際: %d", tc.records, len(info.CAARecords))
+			}
+			if tc.message != "" && !strings.Contains(info.ErrorMessage, tc.message) {
+				t.Errorf("メッセージに %q が含まれていません: %s", tc.message, info.ErrorMessage)
+			}
+		})
+	}
+
+	// 親ドメインへたどる際に、トップレベルドメインは問い合わせない
+	for _, domain := range *queried {
+		if !strings.Contains(domain, ".") {
+			t.Errorf("トップレベルドメインを問い合わせました: %s", domain)
+		}
+	}
+}
+
+// TestCAARecordString CAAレコードの表示形式のテスト
+func TestCAARecordString(t *testing.T) {
+	record := caaRecord{Flag: 0, Tag: "issue", Value: "letsencrypt.org"}
+	if actual := record.String(); actual != `0 issue "letsencrypt.org"` {
+		t.Errorf("期待: %s, 実際: %s", `0 issue "letsencrypt.org"`, actual)
+	}
+}
+
+// caaTestResponse example.com のCAAレコードを返し、それ以外はNXDOMAINを返すテスト用のネームサーバーの応答を作成する
+func caaTestResponse(msg []byte) ([]byte, error) {
+	var query dnsmessage.Message
+	if err := query.Unpack(msg); err != nil {
+		return nil, err
+	}
+	question := query.Questions[0]
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.ID, Response: true, RecursionAvailable: true},
+		Questions: query.Questions,
+	}
+	if question.Name.String() == "example.com." && question.Type == caaType {
+		for _, record := range []caaRecord{{Tag: "issue", Value: "letsencrypt.org"}, {Flag: 128, Tag: "iodef", Value: "mailto:ca@example.com"}} {
+			data := append([]byte{record.Flag, byte(len(record.Tag))}, record.Tag+record.Value...)
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: question.Name, Type: caaType, Class: dnsmessage.ClassINET, TTL: 300},
+				Body:   &dnsmessage.UnknownResource{Type: caaType, Data: data},
+			})
+		}
+	} else {
+		resp.RCode = dnsmessage.RCodeNameError
+	}
+	return resp.Pack()
+}
+
+// TestDNSLookupCAA ネームサーバーへのCAAレコードの問い合わせのテスト
+func TestDNSLookupCAA(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("UDPの待ち受けに失敗: %v", err)
+	}
+	defer conn.Close()
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			packed, err := caaTestResponse(buf[:n])
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(packed, addr)
+		}
+	}()
+
+	resolvConf := filepath.Join(t.TempDir(), "resolv.conf")
+	host, port, _ := net.SplitHostPort(conn.LocalAddr().String())
+	if err := os.WriteFile(resolvConf, []byte("# テスト用\nnameserver "+host+"\n"), 0644); err != nil {
+		t.Fatalf("resolv.confの作成に失敗: %v", err)
+	}
+	original := resolvConfPath
+	resolvConfPath = resolvConf
+	t.Cleanup(func() { resolvConfPath = original })
+	if servers := dnsServers(&Config{}); len(servers) != 1 || servers[0] != host+":53" {
+		t.Errorf("ネームサーバー 期待: %s:53, 実際: %v", host, servers)
+	}
+
+	// テスト用のサーバーは53番ポートではないため、直接問い合わせる
+	server := net.JoinHostPort(host, port)
+	records, err := queryCAA(context.Background(), &Config{}, server, "example.com")
+	if err != nil {
+		t.Fatalf("CAAレコードの取得に失敗: %v", err)
+	}
+	if len(records) != 2 || records[0].String() != `0 issue "letsencrypt.org"` || records[1].String() != `128 iodef "mailto:ca@example.com"` {
+		t.Errorf("CAAレコードが正しくありません: %v", records)
+	}
+
+	records, err = queryCAA(context.Background(), &Config{}, server, "missing.example.com")
+	if err != nil {
+		t.Fatalf("存在しないドメインでエラーが発生しました: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("存在しないドメインでレコードが返されました: %v", records)
+	}
+}
+
+// TestDNSServers CAAレコードの問い合わせに使用するネームサーバーの決定のテスト
+func TestDNSServers(t *testing.T) {
+	// resolv.confがない環境（Windowsなど）
+	original := resolvConfPath
+	resolvConfPath = filepath.Join(t.TempDir(), "missing.conf")
+	t.Cleanup(func() { resolvConfPath = original })
+
+	testCases := []struct {
+		name     string
+		servers  []string
+		expected []string
+	}{
+		{name: "resolv.confがない", expected: []string{"127.0.0.1:53"}},
+		{name: "alert.dns_servers", servers: []string{"192.0.2.53", "198.51.100.53:5353", "2001:db8::53", "[2001:db8::54]", "ns.example.com"},
+			expected: []string{"192.0.2.53:53", "198.51.100.53:5353", "[2001:db8::53]:53", "[2001:db8::54]:53", "ns.example.com:53"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{}
+			config.Alert.DNSServers = tc.servers
+			if servers := dnsServers(config); strings.Join(servers, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("ネームサーバー 期待: %v, 実際: %v", tc.expected, servers)
+			}
+		})
+	}
+}
+
+// TestValidDNSServer alert.dns_serversの形式のテスト
+func TestValidDNSServer(t *testing.T) {
+	for server, expected := range map[string]bool{
+		"192.0.2.53":       true,
+		"192.0.2.53:5353":  true,
+		"2001:db8::53":     true,
+		"[2001:db8::53]":   true,
+		"ns.example.com":   true,
+		"":                 false,
+		":53":              false,
+		"192.0.2.53:0":     false,
+		"192.0.2.53:dns":   false,
+		"192.0.2.53:70000": false,
+	} {
+		if actual := validDNSServer(server); actual != expected {
+			t.Errorf("%q 期待: %v, 実際: %v", server, expected, actual)
+		}
+	}
+}
+
+// TestDNSLookupCAASOCKSProxy alert.socks_proxyを指定した場合にCAAレコードをプロキシ経由（TCP）で問い合わせるテスト
+func TestDNSLookupCAASOCKSProxy(t *testing.T) {
+	// TCPで応答するネームサーバー（長さの接頭辞付き）
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("リスナーの作成に失敗: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(c, length[:]); err != nil {
+					return
+				}
+				msg := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(c, msg); err != nil {
+					return
+				}
+				packed, err := caaTestResponse(msg)
+				if err != nil {
+					return
+				}
+				binary.BigEndian.PutUint16(length[:], uint16(len(packed)))
+				c.Write(append(length[:], packed...))
+			}(conn)
+		}
+	}()
+	port := listener.Addr().(*net.TCPAddr).Port
+	targets := make(chan string, 1)
+	proxyAddress := startSOCKS5Server(t, port, targets)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	// .invalidは直接は名前解決できないため、プロキシ経由で接続した場合だけ問い合わせできる
+	config.Alert.DNSServers = []string{"ns.internal.invalid"}
+	socksProxy, err := parseSOCKSProxy(proxyAddress)
+	if err != nil {
+		t.Fatalf("SOCKS5プロキシの解析に失敗: %v", err)
+	}
+	if config.socksDialer, err = newSOCKSDialer(socksProxy); err != nil {
+		t.Fatalf("SOCKS5プロキシのダイアラーの作成に失敗: %v", err)
+	}
+
+	records, err := dnsLookupCAA(context.Background(), config, "example.com")
+	if err != nil {
+		t.Fatalf("CAAレコードの取得に失敗: %v", err)
+	}
+	if len(records) != 2 || records[0].String() != `0 issue "letsencrypt.org"` {
+		t.Errorf("CAAレコードが正しくありません: %v", records)
+	}
+	select {
+	case target := <-targets:
+		if target != "ns.internal.invalid:53" {
+			t.Errorf("プロキシへの接続先が正しくありません。期待: ns.internal.invalid:53, 実際: %s", target)
+		}
+	default:
+		t.Error("SOCKS5プロキシを経由していません")
+	}
+}
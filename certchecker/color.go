@@ -0,0 +1,52 @@
+package certchecker
+
+import (
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ANSIエスケープシーケンス
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// statusColors ステータスごとの表示色
+var statusColors = map[string]string{
+	"OK":       ansiGreen,
+	"WARNING":  ansiYellow,
+	"CRITICAL": ansiRed,
+	"ERROR":    ansiRed,
+}
+
+// colorizeStatus ステータスを表示色のエスケープシーケンスで囲む（色のないステータスはそのまま返す）
+func colorizeStatus(status string) string {
+	color, ok := statusColors[status]
+	if !ok {
+		return status
+	}
+	return color + status + ansiReset
+}
+
+// IsTerminal ファイルが端末（TTY）かどうかを判定する
+// パイプやファイル、/dev/nullなどの端末ではないキャラクターデバイスにリダイレクトされている場合はfalseを返す
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// ColorSupported ファイルへの出力を色付けしてよいかを判定する
+// 端末であっても、環境変数で色付けが無効にされている場合はfalseを返す
+func ColorSupported(f *os.File) bool {
+	return colorAllowedByEnv() && IsTerminal(f)
+}
+
+// colorAllowedByEnv 環境変数NO_COLORが設定されておらず、TERMがdumbでないか
+func colorAllowedByEnv() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return os.Getenv("TERM") != "dumb"
+}
@@ -0,0 +1,97 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestBuildTextReportColor 色付けを有効にした場合だけエスケープシーケンスが含まれることのテスト
+func TestBuildTextReportColor(t *testing.T) {
+	results := []CertInfo{
+		{SiteName: "OK Site", URL: "ok.example.com", Port: 443, Status: "OK", DaysRemaining: 60},
+		{SiteName: "Warning Site", URL: "warning.example.com", Port: 443, Status: "WARNING", DaysRemaining: 20},
+		{SiteName: "Critical Site", URL: "critical.example.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+		{SiteName: "Error Site", URL: "error.example.com", Port: 443, Status: "ERROR", ErrorMessage: "接続に失敗しました"},
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	colored := buildTextReport(&Config{}, results, true)
+	for _, expected := range []string{
+		"ステータス: " + ansiGreen + "OK" + ansiReset,
+		"ステータス: " + ansiYellow + "WARNING" + ansiReset,
+		"ステータス: " + ansiRed + "CRITICAL" + ansiReset,
+		"ステータス: " + ansiRed + "ERROR" + ansiReset,
+	} {
+		if !strings.Contains(colored, expected) {
+			t.Errorf("色付けしたステータスが含まれていません: %q", expected)
+		}
+	}
+
+	// 色付けしない場合は、エスケープシーケンスを含まずに従来どおり出力する
+	plain := buildTextReport(&Config{}, results, false)
+	if strings.Contains(plain, "\x1b[") {
+		t.Errorf("色付けしないレポートにエスケープシーケンスが含まれています: %q", plain)
+	}
+	if plain != GenerateTextReport(&Config{}, results) {
+		t.Error("GenerateTextReportの出力が色付けしないレポートと一致しません")
+	}
+	if !strings.Contains(plain, "ステータス: WARNING\n") {
+		t.Error("色付けしないレポートにステータスが含まれていません")
+	}
+}
+
+// TestIsTerminal 通常のファイルが端末と判定されないことのテスト
+func TestIsTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatalf("ファイルの作成に失敗: %v", err)
+	}
+	defer f.Close()
+
+	if IsTerminal(f) {
+		t.Error("通常のファイルが端末と判定されました")
+	}
+
+	// /dev/nullはキャラクターデバイスだが端末ではない
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("%s を開けません: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	if IsTerminal(devNull) {
+		t.Errorf("%s が端末と判定されました", os.DevNull)
+	}
+}
+
+// TestColorAllowedByEnv NO_COLORやTERM=dumbが設定されている場合に色付けしないことのテスト
+func TestColorAllowedByEnv(t *testing.T) {
+	testCases := []struct {
+		name     string
+		noColor  bool
+		term     string
+		expected bool
+	}{
+		{name: "指定なし", term: "xterm-256color", expected: true},
+		{name: "NO_COLOR", noColor: true, term: "xterm-256color", expected: false},
+		{name: "TERM=dumb", term: "dumb", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TERM", tc.term)
+			t.Setenv("NO_COLOR", "")
+			if !tc.noColor {
+				os.Unsetenv("NO_COLOR")
+			}
+			if actual := colorAllowedByEnv(); actual != tc.expected {
+				t.Errorf("期待: %v, 実際: %v", tc.expected, actual)
+			}
+		})
+	}
+}
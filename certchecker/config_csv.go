@@ -0,0 +1,105 @@
+package certchecker
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// csvSiteColumns sites_csvで使用できる列（ヘッダー行がない場合はこの順序とみなす）
+var csvSiteColumns = []string{"url", "port", "name"}
+
+// loadSitesCSV sites_csvで指定されたCSVファイルから監視対象のサイトを読み込む
+// 相対パスはメインの設定ファイルのディレクトリを基準とする
+func loadSitesCSV(configPath, csvPath string) ([]Site, error) {
+	if !filepath.IsAbs(csvPath) {
+		csvPath = filepath.Join(filepath.Dir(configPath), csvPath)
+	}
+	f, err := os.Open(csvPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseSitesCSV(f)
+}
+
+// parseSitesCSV url,port,name の列からなるCSVを解析する
+// 1行目の列名がすべて既知の列名の場合はヘッダー行として扱い、列の順序をヘッダーに従う
+// portが空の場合は0（接続時のデフォルトポート）とする
+func parseSitesCSV(r io.Reader) ([]Site, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	columns := map[string]int{}
+	for i, name := range csvSiteColumns {
+		columns[name] = i
+	}
+
+	var sites []Site
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		if first && isCSVHeader(record) {
+			columns = map[string]int{}
+			for i, name := range record {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			if _, ok := columns["url"]; !ok {
+				return nil, fmt.Errorf("%d行目: ヘッダーに url 列がありません", line)
+			}
+			continue
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		site := Site{URL: field("url"), Name: field("name")}
+		if site.URL == "" {
+			return nil, fmt.Errorf("%d行目: url が空です", line)
+		}
+		if port := field("port"); port != "" {
+			site.Port, err = strconv.Atoi(port)
+			if err != nil || site.Port < 1 || site.Port > 65535 {
+				return nil, fmt.Errorf("%d行目: ポート番号が正しくありません: %s", line, port)
+			}
+		}
+		sites = append(sites, site)
+	}
+	return sites, nil
+}
+
+// isCSVHeader CSVの行がヘッダー行（すべての列が既知の列名）かどうか
+func isCSVHeader(record []string) bool {
+	for _, name := range record {
+		known := false
+		for _, column := range csvSiteColumns {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return false
+		}
+	}
+	return len(record) > 0
+}
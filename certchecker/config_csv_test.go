@@ -0,0 +1,102 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigSitesCSV CSVファイルのサイトが設定ファイルのサイトに追加されるテスト
+func TestLoadConfigSitesCSV(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, `sites_csv: hosts.csv
+sites:
+  - url: inline.example.com
+    port: 443
+    name: "設定ファイルのサイト"
+alert:
+  warning_days: 30
+  critical_days: 7
+`)
+	writeTestFile(t, filepath.Join(dir, "hosts.csv"), "url,port,name\nwww.example.com,443,本番サイト\napi.example.com,,API\nmail.example.com,8443\n")
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	expected := []Site{
+		{URL: "inline.example.com", Port: 443, Name: "設定ファイルのサイト"},
+		{URL: "www.example.com", Port: 443, Name: "本番サイト"},
+		{URL: "api.example.com", Name: "API"},
+		{URL: "mail.example.com", Port: 8443},
+	}
+	if !reflect.DeepEqual(config.Sites, expected) {
+		t.Errorf("サイトが正しくありません。\n期待: %+v\n実際: %+v", expected, config.Sites)
+	}
+}
+
+// TestParseSitesCSV CSVの解析のテスト
+func TestParseSitesCSV(t *testing.T) {
+	testCases := []struct {
+		name     string
+		csv      string
+		expected []Site
+		wantErr  string
+	}{
+		{
+			name:     "ヘッダーなし",
+			csv:      "example.com,443,Example\n",
+			expected: []Site{{URL: "example.com", Port: 443, Name: "Example"}},
+		},
+		{
+			name:     "列の順序が異なるヘッダー",
+			csv:      "Name, URL, Port\nExample, example.com, 8443\n",
+			expected: []Site{{URL: "example.com", Port: 8443, Name: "Example"}},
+		},
+		{
+			name:     "コメント行と空行",
+			csv:      "# 監視対象\nurl\n\nexample.com\n",
+			expected: []Site{{URL: "example.com"}},
+		},
+		{
+			name:    "不正なポート番号",
+			csv:     "url,port\nexample.com,https\n",
+			wantErr: "2行目: ポート番号が正しくありません",
+		},
+		{
+			name:    "urlが空",
+			csv:     "url,port,name\n,443,Empty\n",
+			wantErr: "2行目: url が空です",
+		},
+		{
+			name:    "url列のないヘッダー",
+			csv:     "name,port\nExample,443\n",
+			wantErr: "ヘッダーに url 列がありません",
+		},
+	}
+
+	for _, tc := range testCases {
+		sites, err := parseSitesCSV(strings.NewReader(tc.csv))
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: エラーが正しくありません。期待: %s, 実際: %v", tc.name, tc.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: 解析に失敗: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(sites, tc.expected) {
+			t.Errorf("%s: サイトが正しくありません。期待: %+v, 実際: %+v", tc.name, tc.expected, sites)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package certchecker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// expandConfigEnv 認証情報やWebhook URLなどの設定値に含まれる ${VAR} / $VAR を環境変数の値で置き換える
+// パスワードなどを平文で設定ファイルに書かずに済むようにするため。未定義の環境変数を参照している場合はエラーを返す
+func expandConfigEnv(config *Config) error {
+	fields := map[string]*string{
+		"email.smtp.username":   &config.Email.SMTP.Username,
+		"email.smtp.password":   &config.Email.SMTP.Password,
+		"discord.webhook_url":   &config.Discord.WebhookURL,
+		"slack.webhook_url":     &config.Slack.WebhookURL,
+		"teams.webhook_url":     &config.Teams.WebhookURL,
+		"telegram.bot_token":    &config.Telegram.BotToken,
+		"telegram.chat_id":      &config.Telegram.ChatID,
+		"webhook.url":           &config.Webhook.URL,
+		"pagerduty.routing_key": &config.PagerDuty.RoutingKey,
+		"proxy_url":             &config.ProxyURL,
+		"alert.socks_proxy":     &config.Alert.SOCKSProxy,
+	}
+	// エラーメッセージの順序を一定にするため、設定項目名の順に処理する
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		expanded, err := expandEnv(*fields[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", name, err))
+			continue
+		}
+		*fields[name] = expanded
+	}
+
+	for i, webhookURL := range config.Discord.WebhookURLs {
+		expanded, err := expandEnv(webhookURL)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("discord.webhook_urls[%d]: %v", i, err))
+			continue
+		}
+		config.Discord.WebhookURLs[i] = expanded
+	}
+
+	headers := make([]string, 0, len(config.Webhook.Headers))
+	for header := range config.Webhook.Headers {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+	for _, header := range headers {
+		expanded, err := expandEnv(config.Webhook.Headers[header])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook.headers.%s: %v", header, err))
+			continue
+		}
+		config.Webhook.Headers[header] = expanded
+	}
+
+	return errors.Join(errs...)
+}
+
+// expandEnv 文字列中の環境変数を展開する（$$ はそのまま $ として扱う）
+func expandEnv(s string) (string, error) {
+	var missing []string
+	expanded := os.Expand(s, func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("環境変数が設定されていません: %s", strings.Join(missing, ", "))
+	}
+	return expanded, nil
+}
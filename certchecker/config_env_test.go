@@ -0,0 +1,82 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigExpandEnv 設定値の環境変数が展開されることのテスト
+func TestLoadConfigExpandEnv(t *testing.T) {
+	t.Setenv("CERT_CHECKER_SMTP_PASSWORD", "s3cret")
+	t.Setenv("CERT_CHECKER_DISCORD_ID", "12345")
+	t.Setenv("CERT_CHECKER_API_TOKEN", "token-abc")
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := `email:
+  smtp:
+    username: checker
+    password: ${CERT_CHECKER_SMTP_PASSWORD}
+discord:
+  webhook_url: https://discord.com/api/webhooks/$CERT_CHECKER_DISCORD_ID/token
+webhook:
+  headers:
+    Authorization: Bearer ${CERT_CHECKER_API_TOKEN}
+  body: '{{ $x := 1 }}$literal'
+pagerduty:
+  routing_key: pre$$fix
+`
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+	}
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	testCases := map[string]struct {
+		actual   string
+		expected string
+	}{
+		"email.smtp.username":             {config.Email.SMTP.Username, "checker"},
+		"email.smtp.password":             {config.Email.SMTP.Password, "s3cret"},
+		"discord.webhook_url":             {config.Discord.WebhookURL, "https://discord.com/api/webhooks/12345/token"},
+		"webhook.headers.Authorization":   {config.Webhook.Headers["Authorization"], "Bearer token-abc"},
+		"webhook.body（テンプレートは展開しない）":      {config.Webhook.Body, "{{ $x := 1 }}$literal"},
+		"pagerduty.routing_key（$$はエスケープ）": {config.PagerDuty.RoutingKey, "pre$fix"},
+	}
+	for name, tc := range testCases {
+		if tc.actual != tc.expected {
+			t.Errorf("%sが正しくありません。期待: %s, 実際: %s", name, tc.expected, tc.actual)
+		}
+	}
+}
+
+// TestLoadConfigExpandEnvMissing 未定義の環境変数を参照した場合のテスト
+func TestLoadConfigExpandEnvMissing(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "email:\n  smtp:\n    password: ${CERT_CHECKER_UNDEFINED_VARIABLE}\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+	}
+
+	_, err := LoadConfig(configPath)
+	if err == nil {
+		t.Fatal("未定義の環境変数でエラーが発生しませんでした")
+	}
+	for _, expected := range []string{"email.smtp.password", "CERT_CHECKER_UNDEFINED_VARIABLE"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("エラーに %s が含まれていません: %v", expected, err)
+		}
+	}
+}
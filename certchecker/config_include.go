@@ -0,0 +1,102 @@
+package certchecker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// loadIncludes includeで指定された設定ファイルを読み込み、監視対象のサイトをメインの設定ファイルに追加する
+// チームごとにサイトの一覧を別のファイルで管理できるようにするため。相対パスはメインの設定ファイルのディレクトリを基準とする
+// include先で sites 以外の設定を指定する場合は、メインの設定ファイルと同じ値でなければエラーとする
+func loadIncludes(config *Config, path string, data []byte) error {
+	if len(config.Include) == 0 {
+		return nil
+	}
+
+	var mainSettings map[string]interface{}
+	if err := yaml.Unmarshal(data, &mainSettings); err != nil {
+		return err
+	}
+
+	baseDir := filepath.Dir(path)
+	mainPath, _ := filepath.Abs(path)
+	loaded := map[string]bool{mainPath: true}
+	for _, pattern := range config.Include {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(baseDir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			// ワイルドカードを含まないパスは、ファイル名の誤りに気付けるようエラーとする
+			if !strings.ContainsAny(pattern, "*?[") {
+				return fmt.Errorf("%s: ファイルが見つかりません", pattern)
+			}
+			LogWarnf("include: %s に一致するファイルがありません", pattern)
+			continue
+		}
+
+		// 同じファイルに複数のパターンが一致した場合や、メインの設定ファイル自身に一致した場合は1回だけ読み込む
+		for _, file := range matches {
+			absPath, _ := filepath.Abs(file)
+			if loaded[absPath] {
+				continue
+			}
+			loaded[absPath] = true
+
+			sites, err := loadIncludedSites(file, mainSettings)
+			if err != nil {
+				return fmt.Errorf("%s: %v", file, err)
+			}
+			config.Sites = append(config.Sites, sites...)
+		}
+	}
+	return nil
+}
+
+// loadIncludedSites include先の設定ファイルから監視対象のサイトを読み込む
+func loadIncludedSites(path string, mainSettings map[string]interface{}) ([]Site, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings map[string]interface{}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, err
+	}
+	// エラーメッセージの順序を一定にするため、設定項目名の順に確認する
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := settings[key]
+		switch key {
+		case "sites":
+			continue
+		case "include":
+			return nil, fmt.Errorf("include先のファイルでincludeを指定することはできません")
+		}
+		if mainValue, ok := mainSettings[key]; !ok || !reflect.DeepEqual(mainValue, value) {
+			return nil, fmt.Errorf("%s: メインの設定ファイルと異なる値が指定されています（include先では sites 以外の設定はメインの設定ファイルと同じ値にしてください）", key)
+		}
+	}
+
+	var included struct {
+		Sites []Site `yaml:"sites"`
+	}
+	if err := yaml.Unmarshal(data, &included); err != nil {
+		return nil, err
+	}
+	return included.Sites, nil
+}
@@ -0,0 +1,126 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestFile テスト用のファイルを書き込む（ディレクトリがなければ作成する）
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("ディレクトリの作成に失敗: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+	}
+}
+
+// TestLoadConfigInclude includeで指定した設定ファイルのサイトを読み込むテスト
+func TestLoadConfigInclude(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	writeTestFile(t, configPath, `include:
+  - teams/web.yaml
+  - sites.d/*.yaml
+sites:
+  - url: main.example.com
+alert:
+  warning_days: 30
+  critical_days: 7
+`)
+	writeTestFile(t, filepath.Join(dir, "teams", "web.yaml"), `sites:
+  - url: web1.example.com
+  - url: web2.example.com
+`)
+	// メインの設定ファイルと同じ値であれば、sites 以外の設定を指定してもよい
+	writeTestFile(t, filepath.Join(dir, "sites.d", "api.yaml"), `sites:
+  - url: api.example.com
+    port: 8443
+alert:
+  warning_days: 30
+  critical_days: 7
+`)
+
+	// 相対パスはテストの実行ディレクトリではなく、メインの設定ファイルのディレクトリを基準に解決される
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	expected := []string{"main.example.com", "web1.example.com", "web2.example.com", "api.example.com"}
+	if len(config.Sites) != len(expected) {
+		t.Fatalf("サイト数が正しくありません。期待: %d, 実際: %d", len(expected), len(config.Sites))
+	}
+	for i, url := range expected {
+		if config.Sites[i].URL != url {
+			t.Errorf("sites[%d]のURLが正しくありません。期待: %s, 実際: %s", i, url, config.Sites[i].URL)
+		}
+	}
+	if config.Sites[3].Port != 8443 {
+		t.Errorf("include先のサイトのポートが正しくありません。期待: 8443, 実際: %d", config.Sites[3].Port)
+	}
+}
+
+// TestLoadConfigIncludeErrors includeの設定の誤りのテスト
+func TestLoadConfigIncludeErrors(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	testCases := []struct {
+		name     string
+		include  string
+		files    map[string]string
+		expected string
+	}{
+		{
+			name:     "存在しないファイル",
+			include:  "missing.yaml",
+			expected: "ファイルが見つかりません",
+		},
+		{
+			name:     "グローバル設定の競合",
+			include:  "team.yaml",
+			files:    map[string]string{"team.yaml": "sites:\n  - url: team.example.com\nalert:\n  warning_days: 60\n"},
+			expected: "alert: メインの設定ファイルと異なる値",
+		},
+		{
+			name:     "メインにない設定",
+			include:  "team.yaml",
+			files:    map[string]string{"team.yaml": "sites:\n  - url: team.example.com\nstate_file: team.json\n"},
+			expected: "state_file: メインの設定ファイルと異なる値",
+		},
+		{
+			name:     "入れ子のinclude",
+			include:  "team.yaml",
+			files:    map[string]string{"team.yaml": "include:\n  - other.yaml\n"},
+			expected: "includeを指定することはできません",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			configPath := filepath.Join(dir, "config.yaml")
+			writeTestFile(t, configPath, "include:\n  - "+tc.include+"\nsites:\n  - url: main.example.com\nalert:\n  warning_days: 30\n")
+			for name, content := range tc.files {
+				writeTestFile(t, filepath.Join(dir, name), content)
+			}
+
+			_, err := LoadConfig(configPath)
+			if err == nil {
+				t.Fatal("エラーが返されませんでした")
+			}
+			if !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("エラーメッセージが正しくありません。期待: %s を含む, 実際: %v", tc.expected, err)
+			}
+		})
+	}
+}
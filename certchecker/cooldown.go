@@ -0,0 +1,140 @@
+package certchecker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// defaultCooldownFile クールダウンファイルのデフォルトのパス
+const defaultCooldownFile = "cert_checker_cooldown.json"
+
+// cooldownFilePath 最後に通知した日時を保存するファイルのパス
+func cooldownFilePath(config *Config) string {
+	if config.Alert.CooldownFile != "" {
+		return config.Alert.CooldownFile
+	}
+	return defaultCooldownFile
+}
+
+// cooldownTracker サイトとステータスの組み合わせごとに最後に通知した日時を記録し、繰り返しの通知を抑止する
+// 静穏時間帯の判定もここで行い、通知するかどうかをshouldNotifyの1か所で決める
+type cooldownTracker struct {
+	path       string
+	window     time.Duration
+	last       map[string]time.Time
+	now        func() time.Time
+	quietHours func(time.Time) bool // 指定した時刻が静穏時間帯か（nilの場合は静穏時間帯なし）
+}
+
+// loadNotifyTracker 設定に従って通知の判定に使用するトラッカーを作成する
+// alert.cooldown_hoursが指定されている場合はクールダウンファイルを読み込み、時刻にはnotifyNowを使用する
+func loadNotifyTracker(config *Config) (*cooldownTracker, error) {
+	tracker := &cooldownTracker{last: map[string]time.Time{}}
+	var err error
+	if config.Alert.CooldownHours > 0 {
+		tracker, err = loadCooldown(cooldownFilePath(config), time.Duration(config.Alert.CooldownHours)*time.Hour)
+	}
+	tracker.now = notifyNow
+	tracker.quietHours = func(now time.Time) bool { return inQuietHours(config, now) }
+	return tracker, err
+}
+
+// loadCooldown クールダウンファイルを読み込む
+// ファイルが存在しない場合や読み込みに失敗した場合も、空の記録で使用できるトラッカーを返す
+func loadCooldown(path string, window time.Duration) (*cooldownTracker, error) {
+	tracker := &cooldownTracker{
+		path:   path,
+		window: window,
+		last:   map[string]time.Time{},
+		now:    time.Now,
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tracker, nil
+	}
+	if err != nil {
+		return tracker, err
+	}
+	if err := json.Unmarshal(data, &tracker.last); err != nil {
+		tracker.last = map[string]time.Time{}
+		return tracker, fmt.Errorf("クールダウンファイルの解析に失敗: %v", err)
+	}
+	return tracker, nil
+}
+
+// cooldownKey 通知の記録に使用するキー
+func cooldownKey(site, status string) string {
+	return site + "|" + status
+}
+
+// cooldownStatus 通知の記録に使用するステータス
+// エスカレーションレベルが上がった場合はクールダウン期間内でも通知するよう、レベルごとに別のステータスとして記録する
+func cooldownStatus(result CertInfo) string {
+	if result.EscalationLevel > 0 {
+		return fmt.Sprintf("%s#%d", result.Status, result.EscalationLevel)
+	}
+	return result.Status
+}
+
+// shouldNotify 指定したサイトの結果を通知してよいかを判定
+// 静穏時間帯のCRITICAL・ERROR以外の結果は見送り（deferredがtrue）、クールダウン期間内に同じステータスで通知済みの結果は通知しない
+func (c *cooldownTracker) shouldNotify(result CertInfo) (notify, deferred bool) {
+	now := c.now()
+	if c.quietHours != nil && c.quietHours(now) && result.Status != "CRITICAL" && result.Status != "ERROR" {
+		return false, true
+	}
+	if c.window <= 0 {
+		return true, false
+	}
+	last, ok := c.last[cooldownKey(stateKey(result), cooldownStatus(result))]
+	if !ok {
+		return true, false
+	}
+	return now.Sub(last) >= c.window, false
+}
+
+// filter 通知する結果と、静穏時間帯のため見送った結果を返す
+func (c *cooldownTracker) filter(results []CertInfo) (filtered, deferred []CertInfo) {
+	filtered = []CertInfo{}
+	for _, result := range results {
+		notify, wait := c.shouldNotify(result)
+		switch {
+		case notify:
+			filtered = append(filtered, result)
+		case wait:
+			LogInfof("%s - 静穏時間帯のため通知を見送ります (%s)", result.SiteName, result.Status)
+			deferred = append(deferred, result)
+		default:
+			LogInfof("%s - クールダウン期間内のため通知しません (%s)", result.SiteName, result.Status)
+		}
+	}
+	return filtered, deferred
+}
+
+// markNotified 通知した日時を記録する
+func (c *cooldownTracker) markNotified(results []CertInfo) {
+	now := c.now()
+	for _, result := range results {
+		c.last[cooldownKey(stateKey(result), cooldownStatus(result))] = now
+	}
+}
+
+// save 通知した日時をファイルに書き込む（期間を過ぎた記録は削除する）
+func (c *cooldownTracker) save() error {
+	now := c.now()
+	for key, last := range c.last {
+		if now.Sub(last) >= c.window {
+			delete(c.last, key)
+		}
+	}
+
+	data, err := json.MarshalIndent(c.last, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.path, string(data))
+}
@@ -0,0 +1,99 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDispatchNotificationsCooldown クールダウン期間内の2回目の実行で通知しないことのテスト
+func TestDispatchNotificationsCooldown(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := &Config{}
+	config.Discord.Enabled = true
+	config.Discord.WebhookURL = server.URL
+	config.Alert.CooldownHours = 24
+	config.Alert.CooldownFile = filepath.Join(t.TempDir(), "cooldown.json")
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+	}
+
+	// 1回目は通知する
+	DispatchNotifications(context.Background(), config, results)
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("1回目の送信回数が正しくありません。期待: 1, 実際: %d", n)
+	}
+
+	// クールダウン期間内の2回目は通知しない
+	DispatchNotifications(context.Background(), config, results)
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("クールダウン期間内に通知されました: %d回", n)
+	}
+
+	// ステータスが変われば期間内でも通知する
+	results[0].Status = "ERROR"
+	DispatchNotifications(context.Background(), config, results)
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("ステータス変化後の送信回数が正しくありません。期待: 2, 実際: %d", n)
+	}
+}
+
+// TestCooldownTrackerShouldNotify クールダウン期間の判定のテスト
+func TestCooldownTrackerShouldNotify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cooldown.json")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker, err := loadCooldown(path, 6*time.Hour)
+	if err != nil {
+		t.Fatalf("クールダウンファイルの読み込みに失敗: %v", err)
+	}
+	tracker.now = func() time.Time { return now }
+
+	if notify, _ := tracker.shouldNotify(CertInfo{SiteName: "site", Status: "CRITICAL"}); !notify {
+		t.Error("記録がないのに通知が抑止されました")
+	}
+	tracker.markNotified([]CertInfo{{SiteName: "site", Status: "CRITICAL"}})
+	if err := tracker.save(); err != nil {
+		t.Fatalf("クールダウンファイルの保存に失敗: %v", err)
+	}
+
+	// 保存した記録を読み込み直して判定する
+	tracker, err = loadCooldown(path, 6*time.Hour)
+	if err != nil {
+		t.Fatalf("クールダウンファイルの読み込みに失敗: %v", err)
+	}
+	testCases := []struct {
+		name     string
+		elapsed  time.Duration
+		status   string
+		expected bool
+	}{
+		{name: "期間内", elapsed: 5 * time.Hour, status: "CRITICAL", expected: false},
+		{name: "期間経過後", elapsed: 6 * time.Hour, status: "CRITICAL", expected: true},
+		{name: "別のステータス", elapsed: time.Hour, status: "WARNING", expected: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker.now = func() time.Time { return now.Add(tc.elapsed) }
+			if got, _ := tracker.shouldNotify(CertInfo{SiteName: "site", Status: tc.status}); got != tc.expected {
+				t.Errorf("判定が正しくありません。期待: %v, 実際: %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package certchecker
+
+import (
+	"context"
+	"time"
+)
+
+// RunDaemon コンテキストがキャンセルされるまで、一定間隔でrunを繰り返し実行する
+// 実行中にキャンセルされた場合は、その回の実行が終わるのを待ってから戻る
+func RunDaemon(ctx context.Context, interval time.Duration, run func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	LogInfof("常駐モードで起動しました（チェック間隔: %v）", interval)
+	for cycle := 1; ; cycle++ {
+		runCycle(cycle, run)
+
+		select {
+		case <-ctx.Done():
+			LogInfof("停止要求を受け付けたため常駐モードを終了します")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCycle 1回分のチェックを実行し、開始と完了をログに記録する
+func runCycle(cycle int, run func()) {
+	LogInfof("%d回目のチェックを開始します", cycle)
+	start := time.Now()
+	run()
+	LogInfof("%d回目のチェックが完了しました（所要時間: %v）", cycle, time.Since(start).Round(time.Millisecond))
+}
@@ -0,0 +1,72 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+// TestRunDaemon 一定間隔で繰り返し実行され、キャンセルで終了することのテスト
+func TestRunDaemon(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cycles := 0
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunDaemon(ctx, 10*time.Millisecond, func() {
+			cycles++
+			if cycles == 2 {
+				cancel()
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("キャンセル後に常駐モードが終了しませんでした")
+	}
+	if cycles != 2 {
+		t.Errorf("実行回数が正しくありません。期待: 2, 実際: %d", cycles)
+	}
+}
+
+// TestRunDaemonCancelMidCycle 実行中にキャンセルされた場合、その回の実行を終えてから終了することのテスト
+func TestRunDaemonCancelMidCycle(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	started := make(chan struct{})
+	finished := false
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunDaemon(ctx, time.Hour, func() {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			finished = true
+		})
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("キャンセル後に常駐モードが終了しませんでした")
+	}
+	if !finished {
+		t.Error("実行中のチェックが完了する前に終了しました")
+	}
+}
@@ -0,0 +1,80 @@
+package certchecker
+
+import (
+	"fmt"
+)
+
+// consecutiveRuns 今回の結果を含めて同じステータスが何回連続しているか
+// 連続回数を記録していない古い状態ファイルの場合は、前回を1回目とみなす
+func consecutiveRuns(result CertInfo, previous map[string]siteState) int {
+	prev, ok := previous[stateKey(result)]
+	if !ok || prev.Status != result.Status {
+		return 1
+	}
+	if prev.Runs < 1 {
+		return 2
+	}
+	return prev.Runs + 1
+}
+
+// escalationLevel CRITICALの連続回数が到達したalert.escalation_runsのしきい値の数を返す
+func escalationLevel(thresholds []int, runs int) int {
+	level := 0
+	for _, threshold := range thresholds {
+		if runs >= threshold {
+			level++
+		}
+	}
+	return level
+}
+
+// applyEscalation 状態ファイルに記録した連続回数から、CRITICALが続いているサイトのエスカレーションレベルを設定する
+// 放置されたCRITICALを見逃さないよう、レベルが上がった場合はステータスが変わっていなくても通知する（changedResults）
+func applyEscalation(config *Config, results []CertInfo, previous map[string]siteState) {
+	if len(config.Alert.EscalationRuns) == 0 {
+		return
+	}
+	for i := range results {
+		if results[i].Status != "CRITICAL" || results[i].Muted {
+			continue
+		}
+		runs := consecutiveRuns(results[i], previous)
+		results[i].CriticalRuns = runs
+		results[i].EscalationLevel = escalationLevel(config.Alert.EscalationRuns, runs)
+		if results[i].EscalationLevel > 0 {
+			LogInfof("%s - CRITICALが%d回連続しているためエスカレーションします（レベル%d）", results[i].SiteName, runs, results[i].EscalationLevel)
+		}
+	}
+}
+
+// escalated 結果の中にエスカレーション中のサイトがあるか（チャンネル全体へのメンションの判定に使用）
+func escalated(results []CertInfo) bool {
+	for _, result := range results {
+		if result.EscalationLevel > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// escalationLabel 通知のタイトルに付けるエスカレーションの説明
+func escalationLabel(config *Config, cert CertInfo) string {
+	return fmt.Sprintf(config.message("escalation"), cert.EscalationLevel, cert.CriticalRuns)
+}
+
+// pagerDutySeverityOrder PagerDutyのseverity（低い順）
+var pagerDutySeverityOrder = []string{"info", "warning", "error", "critical"}
+
+// escalateSeverity エスカレーションレベルの分だけPagerDutyのseverityを引き上げる（criticalが上限）
+func escalateSeverity(severity string, level int) string {
+	for i, s := range pagerDutySeverityOrder {
+		if s == severity {
+			i += level
+			if i >= len(pagerDutySeverityOrder) {
+				i = len(pagerDutySeverityOrder) - 1
+			}
+			return pagerDutySeverityOrder[i]
+		}
+	}
+	return severity
+}
@@ -0,0 +1,98 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestEscalationConsecutiveRuns CRITICALが連続した回数に応じてエスカレーションレベルが上がることのテスト
+func TestEscalationConsecutiveRuns(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.StateFile = filepath.Join(t.TempDir(), "state.json")
+	config.Alert.EscalationRuns = []int{3, 5}
+
+	testCases := []struct {
+		status   string
+		runs     int
+		level    int
+		notified bool
+	}{
+		{status: "CRITICAL", runs: 1, level: 0, notified: true}, // 初回
+		{status: "CRITICAL", runs: 2, level: 0, notified: false},
+		{status: "CRITICAL", runs: 3, level: 1, notified: true}, // 1つ目のしきい値に到達
+		{status: "CRITICAL", runs: 4, level: 1, notified: false},
+		{status: "CRITICAL", runs: 5, level: 2, notified: true}, // 2つ目のしきい値に到達
+		{status: "CRITICAL", runs: 6, level: 2, notified: false},
+		{status: "OK", runs: 0, level: 0, notified: true},       // 復旧
+		{status: "CRITICAL", runs: 1, level: 0, notified: true}, // 連続回数はリセットされる
+	}
+
+	for i, tc := range testCases {
+		results := []CertInfo{{SiteName: "Example", URL: "example.com", Port: 443, Status: tc.status}}
+
+		previous, err := loadState(config.StateFile)
+		if err != nil {
+			t.Fatalf("%d回目: 状態ファイルの読み込みに失敗: %v", i+1, err)
+		}
+		applyEscalation(config, results, previous)
+		if results[0].CriticalRuns != tc.runs {
+			t.Errorf("%d回目: CriticalRuns 期待: %d, 実際: %d", i+1, tc.runs, results[0].CriticalRuns)
+		}
+		if results[0].EscalationLevel != tc.level {
+			t.Errorf("%d回目: EscalationLevel 期待: %d, 実際: %d", i+1, tc.level, results[0].EscalationLevel)
+		}
+		if notified := len(changedResults(results, previous)) == 1; notified != tc.notified {
+			t.Errorf("%d回目: 通知 期待: %v, 実際: %v", i+1, tc.notified, notified)
+		}
+		if err := saveState(config.StateFile, results, previous, nil); err != nil {
+			t.Fatalf("%d回目: 状態ファイルの書き込みに失敗: %v", i+1, err)
+		}
+	}
+}
+
+// TestEscalationCooldown エスカレーションレベルが上がった場合はクールダウン期間内でも通知することのテスト
+func TestEscalationCooldown(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	tracker, err := loadCooldown(filepath.Join(t.TempDir(), "cooldown.json"), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("クールダウンファイルの読み込みに失敗: %v", err)
+	}
+	result := CertInfo{SiteName: "Example", URL: "example.com", Port: 443, Status: "CRITICAL"}
+	tracker.markNotified([]CertInfo{result})
+
+	if filtered, _ := tracker.filter([]CertInfo{result}); len(filtered) != 0 {
+		t.Errorf("クールダウン期間内の通知が抑止されていません: %+v", filtered)
+	}
+	result.EscalationLevel = 1
+	if filtered, _ := tracker.filter([]CertInfo{result}); len(filtered) != 1 {
+		t.Errorf("エスカレーションした通知が抑止されました")
+	}
+}
+
+// TestEscalateSeverity PagerDutyのseverityの引き上げのテスト
+func TestEscalateSeverity(t *testing.T) {
+	testCases := []struct {
+		severity string
+		level    int
+		expected string
+	}{
+		{severity: "warning", level: 0, expected: "warning"},
+		{severity: "warning", level: 1, expected: "error"},
+		{severity: "warning", level: 5, expected: "critical"},
+		{severity: "critical", level: 1, expected: "critical"},
+		{severity: "unknown", level: 1, expected: "unknown"},
+	}
+	for _, tc := range testCases {
+		if actual := escalateSeverity(tc.severity, tc.level); actual != tc.expected {
+			t.Errorf("escalateSeverity(%q, %d) 期待: %s, 実際: %s", tc.severity, tc.level, tc.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package certchecker
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	// CGoを使わないSQLiteのドライバー（"sqlite"として登録される）
+	_ "modernc.org/sqlite"
+)
+
+// historyDriverName 履歴の保存に使用するdatabase/sqlのドライバー名（modernc.org/sqliteが登録する名前）
+const historyDriverName = "sqlite"
+
+// historySchema 履歴を保存するテーブル（初回の実行時に作成する）
+var historySchema = []string{
+	`CREATE TABLE IF NOT EXISTS check_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	checked_at TEXT NOT NULL,
+	site_name TEXT NOT NULL,
+	url TEXT NOT NULL,
+	port INTEGER NOT NULL,
+	status TEXT NOT NULL,
+	days_remaining INTEGER NOT NULL,
+	not_after TEXT,
+	fingerprint_sha256 TEXT
+)`,
+	`CREATE INDEX IF NOT EXISTS check_history_site ON check_history (url, port, checked_at)`,
+}
+
+// historyInsert 1サイト分のチェック結果を追加するSQL
+const historyInsert = `INSERT INTO check_history (checked_at, site_name, url, port, status, days_remaining, not_after, fingerprint_sha256) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+// historyPrevious サイトの直近の履歴（証明書を取得できたもの）を取得するSQL
+const historyPrevious = `SELECT checked_at, days_remaining FROM check_history WHERE url = ? AND port = ? AND not_after IS NOT NULL ORDER BY checked_at DESC LIMIT 1`
+
+// openHistory 履歴のデータベースを開き、テーブルがなければ作成する
+func openHistory(path string) (*sql.DB, error) {
+	db, err := sql.Open(historyDriverName, path)
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range historySchema {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("テーブルの作成に失敗: %v", err)
+		}
+	}
+	return db, nil
+}
+
+// applyHistoryDelta 前回保存した残り日数からの変化を各サイトの結果に記録する
+// 残り日数が増えた場合は証明書の更新、1日以上経過しても減っていない場合は時刻のずれなどが考えられる
+func applyHistoryDelta(path string, results []CertInfo, now time.Time) error {
+	db, err := openHistory(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	for i := range results {
+		if results[i].Status == "ERROR" {
+			continue
+		}
+		var checkedAt string
+		var previous int
+		err := db.QueryRow(historyPrevious, results[i].URL, results[i].Port).Scan(&checkedAt, &previous)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s の履歴の取得に失敗: %v", results[i].SiteName, err)
+		}
+		delta := results[i].DaysRemaining - previous
+		results[i].DaysRemainingDelta = &delta
+		if last, err := time.Parse(time.RFC3339, checkedAt); err == nil && delta == 0 && now.Sub(last) >= 24*time.Hour {
+			results[i].DaysRemainingStalled = true
+		}
+	}
+	return nil
+}
+
+// daysRemainingDeltaLabel 前回からの残り日数の変化をレポートの言語で表示用に整形する（履歴がない場合は空）
+func daysRemainingDeltaLabel(lang string, delta *int) string {
+	switch {
+	case delta == nil:
+		return ""
+	case *delta > 0:
+		return fmt.Sprintf(reportMessage(lang, "delta_up"), *delta)
+	case *delta < 0:
+		return fmt.Sprintf(reportMessage(lang, "delta_down"), -*delta)
+	default:
+		return reportMessage(lang, "delta_none")
+	}
+}
+
+// recordHistory チェック結果をstorage.sqliteのデータベースに1サイト1行で追加する
+// 有効期限の推移をグラフにできるよう、実行ごとの結果をすべて残す
+func recordHistory(path string, results []CertInfo, checkedAt time.Time) error {
+	db, err := openHistory(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// 1回の実行の結果は、途中で失敗した場合に一部だけ残らないようまとめて追加する
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	timestamp := checkedAt.UTC().Format(time.RFC3339)
+	for _, result := range results {
+		var notAfter, fingerprint interface{}
+		if !result.NotAfter.IsZero() {
+			notAfter = result.NotAfter.UTC().Format(time.RFC3339)
+		}
+		if result.FingerprintSHA256 != "" {
+			fingerprint = result.FingerprintSHA256
+		}
+		if _, err := tx.Exec(historyInsert, timestamp, result.SiteName, result.URL, result.Port,
+			result.Status, result.DaysRemaining, notAfter, fingerprint); err != nil {
+			return fmt.Errorf("%s の履歴の追加に失敗: %v", result.SiteName, err)
+		}
+	}
+	return tx.Commit()
+}
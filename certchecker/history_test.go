@@ -0,0 +1,201 @@
+package certchecker
+
+import (
+	"database/sql"
+	"io"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRecordHistory 実行ごとにサイトごとの履歴が1行ずつ追加されることのテスト
+func TestRecordHistory(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK", DaysRemaining: 60,
+			NotAfter: time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC), FingerprintSHA256: "AB:CD"},
+		{SiteName: "Broken", URL: "broken.example.com", Port: 443, Status: "ERROR"},
+	}
+
+	path := filepath.Join(t.TempDir(), "history.db")
+	first := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 2; i++ {
+		if err := recordHistory(path, results, first.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("%d回目の履歴の保存に失敗: %v", i+1, err)
+		}
+	}
+
+	db, err := openHistory(path)
+	if err != nil {
+		t.Fatalf("履歴のデータベースを開けません: %v", err)
+	}
+	defer db.Close()
+
+	for _, result := range results {
+		var count int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM check_history WHERE url = ?`, result.URL).Scan(&count); err != nil {
+			t.Fatalf("行数の取得に失敗: %v", err)
+		}
+		if count != 2 {
+			t.Errorf("%s の行数 期待: 2, 実際: %d", result.URL, count)
+		}
+	}
+
+	// 列の値の確認
+	var checkedAt, status string
+	var daysRemaining int
+	var notAfter, fingerprint sql.NullString
+	row := db.QueryRow(`SELECT checked_at, status, days_remaining, not_after, fingerprint_sha256 FROM check_history WHERE url = ? ORDER BY id LIMIT 1`, "example.com")
+	if err := row.Scan(&checkedAt, &status, &daysRemaining, &notAfter, &fingerprint); err != nil {
+		t.Fatalf("行の取得に失敗: %v", err)
+	}
+	if checkedAt != "2026-10-15T09:00:00Z" {
+		t.Errorf("checked_at 期待: 2026-10-15T09:00:00Z, 実際: %v", checkedAt)
+	}
+	if status != "OK" || daysRemaining != 60 {
+		t.Errorf("status, days_remaining 期待: OK, 60, 実際: %v, %v", status, daysRemaining)
+	}
+	if notAfter.String != "2026-12-15T00:00:00Z" || fingerprint.String != "AB:CD" {
+		t.Errorf("not_after, fingerprint_sha256 期待: 2026-12-15T00:00:00Z, AB:CD, 実際: %v, %v", notAfter.String, fingerprint.String)
+	}
+
+	// 証明書を取得できなかったサイトは有効期限とフィンガープリントをNULLとする
+	row = db.QueryRow(`SELECT not_after, fingerprint_sha256 FROM check_history WHERE url = ? ORDER BY id LIMIT 1`, "broken.example.com")
+	if err := row.Scan(&notAfter, &fingerprint); err != nil {
+		t.Fatalf("行の取得に失敗: %v", err)
+	}
+	if notAfter.Valid || fingerprint.Valid {
+		t.Errorf("ERRORの行の not_after, fingerprint_sha256 期待: NULL, 実際: %v, %v", notAfter.String, fingerprint.String)
+	}
+}
+
+// TestApplyHistoryDelta 前回保存した残り日数からの変化のテスト
+func TestApplyHistoryDelta(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	notAfter := time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC)
+	yesterday := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	now := yesterday.AddDate(0, 0, 1)
+
+	// 前日の結果を保存しておく
+	path := filepath.Join(t.TempDir(), "history.db")
+	seed := []CertInfo{
+		{SiteName: "Decreasing", URL: "decreasing.example.com", Port: 443, Status: "OK", DaysRemaining: 61, NotAfter: notAfter},
+		{SiteName: "Renewed", URL: "renewed.example.com", Port: 443, Status: "WARNING", DaysRemaining: 10, NotAfter: notAfter},
+		{SiteName: "Stalled", URL: "stalled.example.com", Port: 443, Status: "OK", DaysRemaining: 60, NotAfter: notAfter},
+		{SiteName: "Broken", URL: "broken.example.com", Port: 443, Status: "ERROR"},
+	}
+	if err := recordHistory(path, seed, yesterday); err != nil {
+		t.Fatalf("履歴の保存に失敗: %v", err)
+	}
+
+	results := []CertInfo{
+		{SiteName: "Decreasing", URL: "decreasing.example.com", Port: 443, Status: "OK", DaysRemaining: 60},
+		{SiteName: "Renewed", URL: "renewed.example.com", Port: 443, Status: "OK", DaysRemaining: 89},
+		{SiteName: "Stalled", URL: "stalled.example.com", Port: 443, Status: "OK", DaysRemaining: 60},
+		// 前回は証明書を取得できなかったため比較しない
+		{SiteName: "Broken", URL: "broken.example.com", Port: 443, Status: "OK", DaysRemaining: 30},
+		{SiteName: "New", URL: "new.example.com", Port: 443, Status: "OK", DaysRemaining: 90},
+	}
+	if err := applyHistoryDelta(path, results, now); err != nil {
+		t.Fatalf("履歴の読み込みに失敗: %v", err)
+	}
+
+	testCases := []struct {
+		delta   *int
+		stalled bool
+		label   string
+	}{
+		{delta: intPtr(-1), label: "↓1日"},
+		{delta: intPtr(79), label: "↑79日、証明書が更新されています"},
+		{delta: intPtr(0), stalled: true, label: "変化なし"},
+		{delta: nil},
+		{delta: nil},
+	}
+	for i, tc := range testCases {
+		result := results[i]
+		if (tc.delta == nil) != (result.DaysRemainingDelta == nil) ||
+			(tc.delta != nil && *tc.delta != *result.DaysRemainingDelta) {
+			t.Errorf("%s: DaysRemainingDelta 期待: %v, 実際: %v", result.SiteName, formatIntPtr(tc.delta), formatIntPtr(result.DaysRemainingDelta))
+		}
+		if result.DaysRemainingStalled != tc.stalled {
+			t.Errorf("%s: DaysRemainingStalled 期待: %v, 実際: %v", result.SiteName, tc.stalled, result.DaysRemainingStalled)
+		}
+		if label := daysRemainingDeltaLabel(languageJapanese, result.DaysRemainingDelta); label != tc.label {
+			t.Errorf("%s: 表示 期待: %q, 実際: %q", result.SiteName, tc.label, label)
+		}
+	}
+
+	// テキストレポートに前回からの変化が表示される
+	report := buildTextReport(&Config{}, results, false)
+	for _, expected := range []string{"残り日数: 60日（前回から↓1日）", "（前回から↑79日、証明書が更新されています）", "残り日数が減っていません"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("テキストレポートに %q が含まれていません", expected)
+		}
+	}
+}
+
+// TestApplyHistoryDeltaLatestRecord 複数回の履歴がある場合に、同じURL・ポートで証明書を取得できた直近の結果と比較するテスト
+func TestApplyHistoryDeltaLatestRecord(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	notAfter := time.Date(2026, 12, 15, 0, 0, 0, 0, time.UTC)
+	first := time.Date(2026, 10, 13, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	runs := [][]CertInfo{
+		{
+			{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK", DaysRemaining: 63, NotAfter: notAfter},
+			{SiteName: "Example 8443", URL: "example.com", Port: 8443, Status: "OK", DaysRemaining: 30, NotAfter: notAfter},
+		},
+		{
+			{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK", DaysRemaining: 62, NotAfter: notAfter},
+			{SiteName: "Example 8443", URL: "example.com", Port: 8443, Status: "OK", DaysRemaining: 29, NotAfter: notAfter},
+		},
+		// 直近の実行では443番ポートの証明書を取得できなかった
+		{
+			{SiteName: "Example", URL: "example.com", Port: 443, Status: "ERROR"},
+			{SiteName: "Example 8443", URL: "example.com", Port: 8443, Status: "OK", DaysRemaining: 28, NotAfter: notAfter},
+		},
+	}
+	for i, run := range runs {
+		if err := recordHistory(path, run, first.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("%d回目の履歴の保存に失敗: %v", i+1, err)
+		}
+	}
+
+	results := []CertInfo{
+		{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK", DaysRemaining: 60},
+		{SiteName: "Example 8443", URL: "example.com", Port: 8443, Status: "OK", DaysRemaining: 27},
+	}
+	if err := applyHistoryDelta(path, results, first.AddDate(0, 0, 3)); err != nil {
+		t.Fatalf("履歴の読み込みに失敗: %v", err)
+	}
+
+	// 443番ポートはERRORの行を飛ばして2回目の62日と、8443番ポートは3回目の28日と比較する
+	expected := []int{-2, -1}
+	for i, result := range results {
+		if result.DaysRemainingDelta == nil || *result.DaysRemainingDelta != expected[i] {
+			t.Errorf("%s: DaysRemainingDelta 期待: %d, 実際: %s", result.SiteName, expected[i], formatIntPtr(result.DaysRemainingDelta))
+		}
+		if result.DaysRemainingStalled {
+			t.Errorf("%s: DaysRemainingStalled 期待: false, 実際: true", result.SiteName)
+		}
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func formatIntPtr(v *int) string {
+	if v == nil {
+		return "nil"
+	}
+	return strconv.Itoa(*v)
+}
@@ -0,0 +1,130 @@
+package certchecker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// LeafCertificate 鍵の種類ごとに取得したリーフ証明書の概要（check_both_keytypes指定時）
+type LeafCertificate struct {
+	KeyType           string    `json:"key_type"`
+	KeyBits           int       `json:"key_bits"`
+	Subject           string    `json:"subject"`
+	Issuer            string    `json:"issuer"`
+	NotAfter          time.Time `json:"not_after"`
+	DaysRemaining     int       `json:"days_remaining"`
+	FingerprintSHA256 string    `json:"fingerprint_sha256"`
+}
+
+// keyTypeCipherSuites 鍵の種類ごとの、その鍵の証明書でしか使えないTLS 1.2の暗号スイート
+// TLS 1.3では暗号スイートと証明書の鍵の種類が無関係なため、TLS 1.2に制限して証明書を選ばせる
+var keyTypeCipherSuites = []struct {
+	keyType string
+	suites  []uint16
+}{
+	{
+		keyType: "ECDSA",
+		suites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+		},
+	},
+	{
+		keyType: "RSA",
+		suites: []uint16{
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+			tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+		},
+	},
+}
+
+// collectLeafCertificates 暗号スイートを鍵の種類ごとに制限して接続し直し、サーバーが提示するリーフ証明書をすべて取得する
+// その鍵の種類の証明書を持たないサーバーではハンドシェイクに失敗するため、失敗した種類は結果に含めない
+// TLS 1.3のみのサーバーやタイムアウトなど、それ以外の理由で確認できなかった場合はエラーを返す
+func collectLeafCertificates(ctx context.Context, dialer contextDialer, timeout time.Duration, address string, conf *tls.Config, starttls string) ([]LeafCertificate, error) {
+	var leaves []LeafCertificate
+	var errs, missing []error
+	for _, kt := range keyTypeCipherSuites {
+		keyTypeConf := conf.Clone()
+		keyTypeConf.MaxVersion = tls.VersionTLS12
+		keyTypeConf.CipherSuites = kt.suites
+
+		conn, err := dialTLS(ctx, dialer, timeout, address, keyTypeConf, starttls)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", kt.keyType, err)
+			if isMissingKeyTypeError(err) {
+				LogDebugf("%s - %s証明書はありません: %v", address, kt.keyType, err)
+				missing = append(missing, err)
+				continue
+			}
+			LogWarnf("%s - %s証明書を取得できませんでした: %v", address, kt.keyType, err)
+			errs = append(errs, err)
+			continue
+		}
+		certs := conn.ConnectionState().PeerCertificates
+		conn.Close()
+		if len(certs) == 0 {
+			continue
+		}
+		leaves = append(leaves, leafCertificate(certs[0]))
+	}
+	// どの鍵の種類でも証明書を取得できなかった場合は、確認そのものができていない
+	if len(leaves) == 0 {
+		errs = append(errs, missing...)
+	}
+	return leaves, errors.Join(errs...)
+}
+
+// isMissingKeyTypeError サーバーがその鍵の種類の証明書を持たないため、ハンドシェイクを拒否された（handshake_failureのアラート）か
+// TLS 1.3のみのサーバーはprotocol_versionのアラートを返すため、これには該当しない
+func isMissingKeyTypeError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "remote error" && opErr.Err.Error() == "tls: handshake failure"
+}
+
+// leafCertificate 証明書の概要を作成
+func leafCertificate(cert *x509.Certificate) LeafCertificate {
+	daysRemaining, _ := remainingDays(cert.NotAfter, time.Now())
+	leaf := LeafCertificate{
+		Subject:           cert.Subject.CommonName,
+		Issuer:            issuerName(cert),
+		NotAfter:          cert.NotAfter,
+		DaysRemaining:     daysRemaining,
+		FingerprintSHA256: certFingerprint(cert),
+	}
+	leaf.KeyType, leaf.KeyBits = publicKeyInfo(cert)
+	return leaf
+}
+
+// checkLeafCertificates 最初の接続で取得したもの以外の証明書の有効期限を確認する
+// 新しいクライアントにはECDSA、古いクライアントにはRSAの証明書を提示するサーバーで、片方だけ更新し忘れるのを検知する
+func checkLeafCertificates(config *Config, info *CertInfo) {
+	for _, leaf := range info.LeafCertificates {
+		if leaf.FingerprintSHA256 == info.FingerprintSHA256 {
+			continue
+		}
+		switch {
+		case leaf.DaysRemaining < 0:
+			info.addProblem("CRITICAL", fmt.Sprintf(config.message("problem_leaf_expired"), leaf.KeyType, -leaf.DaysRemaining))
+		case leaf.DaysRemaining <= info.CriticalDays:
+			info.addProblem("CRITICAL", fmt.Sprintf(config.message("problem_leaf_remaining"), leaf.KeyType, leaf.DaysRemaining))
+		case leaf.DaysRemaining <= info.WarningDays:
+			info.addProblem("WARNING", fmt.Sprintf(config.message("problem_leaf_remaining"), leaf.KeyType, leaf.DaysRemaining))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package certchecker
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCheckCertificateBothKeytypes ECDSAとRSAの証明書を使い分けるサーバーで両方の証明書を取得するテスト
+func TestCheckCertificateBothKeytypes(t *testing.T) {
+	ecdsaCert := newTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "dual.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		NotAfter:    time.Now().AddDate(0, 0, 90),
+	}, nil)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("鍵の生成に失敗: %v", err)
+	}
+	// 古いクライアント向けのRSA証明書だけ更新し忘れている状態
+	rsaCert := newTestCertWithKey(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "dual.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		NotAfter:    time.Now().AddDate(0, 0, 5),
+	}, rsaKey, nil)
+	port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{ecdsaCert.tlsCertificate(), rsaCert.tlsCertificate()}})
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+
+	// 指定しない場合は1つの証明書しか取得しない
+	result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "Dual"})
+	if len(result.LeafCertificates) != 0 {
+		t.Errorf("check_both_keytypesを指定していないのに証明書が取得されました: %v", result.LeafCertificates)
+	}
+
+	result = CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "Dual", CheckBothKeytypes: true})
+	if len(result.LeafCertificates) != 2 {
+		t.Fatalf("取得した証明書の数が正しくありません。期待: 2, 実際: %d (%s)", len(result.LeafCertificates), result.ErrorMessage)
+	}
+	fingerprints := map[string]string{
+		"ECDSA": certFingerprint(ecdsaCert.cert),
+		"RSA":   certFingerprint(rsaCert.cert),
+	}
+	for _, leaf := range result.LeafCertificates {
+		if leaf.FingerprintSHA256 != fingerprints[leaf.KeyType] {
+			t.Errorf("%s証明書のフィンガープリントが正しくありません: %s", leaf.KeyType, leaf.FingerprintSHA256)
+		}
+		delete(fingerprints, leaf.KeyType)
+	}
+	if len(fingerprints) != 0 {
+		t.Errorf("取得できなかった証明書があります: %v", fingerprints)
+	}
+
+	// どちらの証明書で最初に接続したかに関係なく、期限の近いRSA証明書によりCRITICALとなる
+	if result.Status != "CRITICAL" {
+		t.Errorf("ステータスが正しくありません。期待: CRITICAL, 実際: %s (%s)", result.Status, result.ErrorMessage)
+	}
+	if report := GenerateTextReport(config, []CertInfo{result}); !strings.Contains(report, "鍵の種類ごとの証明書:") || !strings.Contains(report, "RSA 2048ビット") {
+		t.Errorf("テキストレポートに鍵の種類ごとの証明書が含まれていません:\n%s", report)
+	}
+}
+
+// TestCollectLeafCertificatesSingleKeytype 1種類の証明書しか持たないサーバーでは取得できた証明書だけを返すテスト
+func TestCollectLeafCertificatesSingleKeytype(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "ecdsa-only.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}, nil)
+	port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{cert.tlsCertificate()}})
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	leaves, err := collectLeafCertificates(context.Background(), &net.Dialer{Timeout: 5 * time.Second}, 5*time.Second, address, &tls.Config{InsecureSkipVerify: true}, "")
+	if err != nil {
+		t.Errorf("証明書を持たない鍵の種類がエラーとして報告されました: %v", err)
+	}
+	if len(leaves) != 1 || leaves[0].KeyType != "ECDSA" {
+		t.Errorf("取得した証明書が正しくありません: %v", leaves)
+	}
+}
+
+// TestCheckCertificateBothKeytypesTLS13Only TLS 1.3のみのサーバーでは鍵の種類ごとの確認ができなかったことをWARNINGとして報告するテスト
+func TestCheckCertificateBothKeytypesTLS13Only(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "modern.example.com"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+	}, nil)
+	port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{cert.tlsCertificate()}, MinVersion: tls.VersionTLS13})
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+	trustTestCerts(config, cert.cert)
+
+	result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "Modern", CheckBothKeytypes: true})
+	if result.Status != "WARNING" {
+		t.Errorf("ステータスが正しくありません。期待: WARNING, 実際: %s (%s)", result.Status, result.ErrorMessage)
+	}
+	if !strings.Contains(result.ErrorMessage, "鍵の種類ごとの証明書を確認できませんでした") {
+		t.Errorf("確認できなかったことが報告されていません: %s", result.ErrorMessage)
+	}
+	if len(result.LeafCertificates) != 0 {
+		t.Errorf("取得できないはずの証明書が含まれています: %v", result.LeafCertificates)
+	}
+}
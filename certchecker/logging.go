@@ -0,0 +1,136 @@
+package certchecker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// logHandler JSON形式でログを出力する場合のハンドラー（テキスト形式の場合はnil）
+var logHandler slog.Handler
+
+// logLevel 出力する最低のログレベル（logging.levelで変更）
+var logLevel = slog.LevelInfo
+
+// logLanguage ログのメッセージの言語（logging.languageで変更）
+var logLanguage = languageJapanese
+
+// syslogWriter syslogへの出力先（ログレベルに応じた重大度で書き込む）
+type syslogWriter interface {
+	Write(p []byte) (int, error)
+	Close() error
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+}
+
+// logSyslog syslogに出力する場合の出力先（logging.syslogが無効の場合はnil）
+var logSyslog syslogWriter
+
+// defaultSyslogTag syslogのタグ（プログラム名）のデフォルト値
+const defaultSyslogTag = "cert-checker"
+
+// syslogFacilities 設定ファイルで指定できるsyslogのファシリティ（RFC 5424の値を8倍したもの）
+var syslogFacilities = map[string]int{
+	"kern": 0 << 3, "user": 1 << 3, "mail": 2 << 3, "daemon": 3 << 3,
+	"auth": 4 << 3, "syslog": 5 << 3, "lpr": 6 << 3, "news": 7 << 3,
+	"uucp": 8 << 3, "cron": 9 << 3, "authpriv": 10 << 3, "ftp": 11 << 3,
+	"local0": 16 << 3, "local1": 17 << 3, "local2": 18 << 3, "local3": 19 << 3,
+	"local4": 20 << 3, "local5": 21 << 3, "local6": 22 << 3, "local7": 23 << 3,
+}
+
+// parseSyslogFacility syslogのファシリティ名を解析する（大文字小文字は区別せず、省略時はdaemon）
+func parseSyslogFacility(name string) (int, error) {
+	if name == "" {
+		return syslogFacilities["daemon"], nil
+	}
+	facility, ok := syslogFacilities[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("未対応のファシリティです: %s", name)
+	}
+	return facility, nil
+}
+
+// writeSyslog ログレベルに応じた重大度でsyslogに書き込む
+func writeSyslog(w syslogWriter, level slog.Level, msg string) error {
+	switch {
+	case level >= slog.LevelError:
+		return w.Err(msg)
+	case level >= slog.LevelWarn:
+		return w.Warning(msg)
+	case level >= slog.LevelInfo:
+		return w.Info(msg)
+	default:
+		return w.Debug(msg)
+	}
+}
+
+// parseLogLevel 設定ファイルのログレベル（DEBUG, INFO, WARNING, ERROR, CRITICAL）を解析する
+// 大文字小文字は区別せず、省略時はINFOとする
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToUpper(level) {
+	case "DEBUG":
+		return slog.LevelDebug, nil
+	case "", "INFO":
+		return slog.LevelInfo, nil
+	case "WARN", "WARNING":
+		return slog.LevelWarn, nil
+	case "ERROR", "CRITICAL":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("未対応のログレベルです: %s", level)
+}
+
+// logMessagef logging.languageの表記で書式文字列に引数を埋め込む
+// 英語の表記はmessages_log.goで日本語の書式文字列をキーとして引き、ない場合は日本語のまま出力する
+func logMessagef(format string, args ...any) string {
+	if logLanguage != languageJapanese {
+		if message, ok := logMessages[format]; ok {
+			format = message
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// logEvent サイトやステータスなどの付加情報を付けて、指定したレベルでログを出力する
+// JSON形式では付加情報を個別のフィールドとして出力し、テキスト形式ではレベルとメッセージのみを出力する
+func logEvent(level slog.Level, msg string, attrs ...slog.Attr) {
+	if level < logLevel {
+		return
+	}
+	if logHandler == nil {
+		// syslogにはログレベルを重大度として付けて送る
+		if logSyslog != nil {
+			writeSyslog(logSyslog, level, msg)
+			return
+		}
+		Logger.Printf("[%s] %s", level, msg)
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, msg, 0)
+	record.AddAttrs(attrs...)
+	logHandler.Handle(context.Background(), record)
+}
+
+// LogDebugf 詳細な動作の確認用のログを出力する
+func LogDebugf(format string, args ...any) {
+	logEvent(slog.LevelDebug, logMessagef(format, args...))
+}
+
+// LogInfof 通常の動作のログを出力する
+func LogInfof(format string, args ...any) {
+	logEvent(slog.LevelInfo, logMessagef(format, args...))
+}
+
+// LogWarnf 処理は続けられるが確認が必要な事象のログを出力する
+func LogWarnf(format string, args ...any) {
+	logEvent(slog.LevelWarn, logMessagef(format, args...))
+}
+
+// LogErrorf 処理に失敗した場合のログを出力する
+func LogErrorf(format string, args ...any) {
+	logEvent(slog.LevelError, logMessagef(format, args...))
+}
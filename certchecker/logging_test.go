@@ -0,0 +1,270 @@
+package certchecker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// TestSetupLoggerJSON JSON形式のログ出力のテスト
+func TestSetupLoggerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{LogOutput: &buf}
+	config.Logging.Format = "json"
+
+	// ロガーのセットアップ
+	SetupLogger(config)
+	t.Cleanup(func() { logHandler = nil })
+
+	logEvent(slog.LevelInfo, "チェック完了: Example (WARNING)", slog.String("site", "Example"), slog.String("status", "WARNING"))
+	Logger.Printf("%dサイトのチェックを開始します", 3)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("ログの行数が正しくありません。期待: 2, 実際: %d (%s)", len(lines), buf.String())
+	}
+
+	var event map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("ログをJSONとして解析できません: %v (%s)", err, lines[0])
+	}
+	expected := map[string]string{
+		"level":  "INFO",
+		"msg":    "チェック完了: Example (WARNING)",
+		"site":   "Example",
+		"status": "WARNING",
+	}
+	for key, value := range expected {
+		if event[key] != value {
+			t.Errorf("%sが正しくありません。期待: %s, 実際: %v", key, value, event[key])
+		}
+	}
+	if _, ok := event["time"]; !ok {
+		t.Error("タイムスタンプが出力されていません")
+	}
+
+	// 付加情報のないログもJSONとして出力される
+	var plain map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &plain); err != nil {
+		t.Fatalf("ログをJSONとして解析できません: %v (%s)", err, lines[1])
+	}
+	if plain["msg"] != "3サイトのチェックを開始します" {
+		t.Errorf("メッセージが正しくありません: %v", plain["msg"])
+	}
+}
+
+// TestSetupLoggerText テキスト形式では付加情報を出力しないことのテスト
+func TestSetupLoggerText(t *testing.T) {
+	var buf bytes.Buffer
+	config := &Config{LogOutput: &buf}
+
+	// ロガーのセットアップ
+	SetupLogger(config)
+
+	logEvent(slog.LevelInfo, "チェック完了: Example (WARNING)", slog.String("site", "Example"))
+
+	output := buf.String()
+	if !strings.HasSuffix(output, "[INFO] チェック完了: Example (WARNING)\n") {
+		t.Errorf("テキスト形式のログが正しくありません: %s", output)
+	}
+	if strings.Contains(output, "{") {
+		t.Errorf("テキスト形式のログがJSONになっています: %s", output)
+	}
+}
+
+// TestLogLevel 設定したログレベル未満のログが出力されないことのテスト
+func TestLogLevel(t *testing.T) {
+	t.Cleanup(func() { logLevel = slog.LevelInfo })
+
+	testCases := []struct {
+		level       string
+		expectDebug bool
+		expectInfo  bool
+		expectWarn  bool
+	}{
+		{level: "", expectDebug: false, expectInfo: true, expectWarn: true},
+		{level: "info", expectDebug: false, expectInfo: true, expectWarn: true},
+		{level: "DEBUG", expectDebug: true, expectInfo: true, expectWarn: true},
+		{level: "WARNING", expectDebug: false, expectInfo: false, expectWarn: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level, func(t *testing.T) {
+			var buf bytes.Buffer
+			config := &Config{LogOutput: &buf, Sites: []Site{{Name: "Missing", File: "/nonexistent/cert.pem"}}}
+			config.Logging.Level = tc.level
+
+			// ロガーのセットアップ
+			SetupLogger(config)
+
+			CheckAllSites(context.Background(), config)
+			output := buf.String()
+
+			checks := []struct {
+				name     string
+				message  string
+				expected bool
+			}{
+				{"DEBUG", "[DEBUG] チェック開始: Missing", tc.expectDebug},
+				{"INFO", "[INFO] 1サイトのチェックを開始します", tc.expectInfo},
+				{"WARN", "[WARN] /nonexistent/cert.pem - 証明書ファイルの読み込みに失敗", tc.expectWarn},
+			}
+			for _, check := range checks {
+				if strings.Contains(output, check.message) != check.expected {
+					t.Errorf("%sのログの出力が正しくありません。期待: %v, 出力: %s", check.name, check.expected, output)
+				}
+			}
+		})
+	}
+}
+
+// TestLogLanguage logging.language: en でログが英語で出力されることのテスト
+func TestLogLanguage(t *testing.T) {
+	t.Cleanup(func() { logLanguage = languageJapanese })
+
+	var buf bytes.Buffer
+	config := &Config{LogOutput: &buf, Sites: []Site{{Name: "Missing", File: "/nonexistent/cert.pem"}}}
+	config.Logging.Language = languageEnglish
+
+	// ロガーのセットアップ
+	SetupLogger(config)
+
+	CheckAllSites(context.Background(), config)
+	output := buf.String()
+
+	for _, expected := range []string{"[INFO] Starting checks for 1 sites", "[INFO] Check finished: Missing (ERROR)", "[INFO] Finished checking all sites"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("英語のログが出力されていません。期待: %s, 出力: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "チェックを開始します") {
+		t.Errorf("日本語のログが出力されています: %s", output)
+	}
+
+	// logging.languageを省略した場合は日本語のまま
+	buf.Reset()
+	config.Logging.Language = ""
+	SetupLogger(config)
+	LogInfof("%dサイトのチェックを開始します", 1)
+	if !strings.HasSuffix(buf.String(), "[INFO] 1サイトのチェックを開始します\n") {
+		t.Errorf("日本語のログが正しくありません: %s", buf.String())
+	}
+}
+
+// TestLogMessagesComplete 日本語のログの書式文字列すべてに英語の表記があることのテスト
+// LogDebugfなどとlogMessagefに渡した書式文字列をソースから集め（main.goを含む）、logMessagesと照合する
+func TestLogMessagesComplete(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("ソースファイルの一覧の取得に失敗: %v", err)
+	}
+	files = append(files, filepath.Join("..", "main.go"))
+
+	used := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatalf("%s の解析に失敗: %v", file, err)
+		}
+		ast.Inspect(parsed, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			var name string
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name // main.goのcertchecker.LogInfofなど
+			}
+			switch name {
+			case "LogDebugf", "LogInfof", "LogWarnf", "LogErrorf", "logMessagef":
+			default:
+				return true
+			}
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			format, err := strconv.Unquote(literal.Value)
+			if err != nil || utf8.RuneCountInString(format) == len(format) {
+				return true // 日本語を含まない書式文字列は翻訳しない
+			}
+			used[format] = true
+			if _, ok := logMessages[format]; !ok {
+				t.Errorf("%s: 英語の表記がありません: %q", fset.Position(literal.Pos()), format)
+			}
+			return true
+		})
+	}
+
+	for format, message := range logMessages {
+		if !used[format] {
+			t.Errorf("使用されていない表記があります: %q", format)
+		}
+		if strings.Count(format, "%") != strings.Count(message, "%") {
+			t.Errorf("書式指定の数が一致しません: %q と %q", format, message)
+		}
+	}
+}
+
+// TestParseLogLevel ログレベルの解析のテスト
+func TestParseLogLevel(t *testing.T) {
+	testCases := map[string]slog.Level{
+		"":         slog.LevelInfo,
+		"debug":    slog.LevelDebug,
+		"INFO":     slog.LevelInfo,
+		"WARNING":  slog.LevelWarn,
+		"warn":     slog.LevelWarn,
+		"ERROR":    slog.LevelError,
+		"CRITICAL": slog.LevelError,
+	}
+	for input, expected := range testCases {
+		level, err := parseLogLevel(input)
+		if err != nil {
+			t.Errorf("%q の解析でエラーが発生しました: %v", input, err)
+		} else if level != expected {
+			t.Errorf("%q の解析結果が正しくありません。期待: %v, 実際: %v", input, expected, level)
+		}
+	}
+
+	if _, err := parseLogLevel("verbose"); err == nil {
+		t.Error("未対応のログレベルでエラーが発生しませんでした")
+	}
+}
+
+// TestParseSyslogFacility syslogのファシリティの解析のテスト
+func TestParseSyslogFacility(t *testing.T) {
+	testCases := map[string]int{
+		"":       3 << 3,
+		"daemon": 3 << 3,
+		"LOCAL0": 16 << 3,
+		"local7": 23 << 3,
+	}
+	for input, expected := range testCases {
+		facility, err := parseSyslogFacility(input)
+		if err != nil {
+			t.Errorf("%q の解析でエラーが発生しました: %v", input, err)
+		} else if facility != expected {
+			t.Errorf("%q の解析結果が正しくありません。期待: %d, 実際: %d", input, expected, facility)
+		}
+	}
+
+	if _, err := parseSyslogFacility("local8"); err == nil {
+		t.Error("未対応のファシリティでエラーが発生しませんでした")
+	}
+}
@@ -0,0 +1,233 @@
+package certchecker
+
+import "fmt"
+
+// レポートとログの言語（report.language, logging.language）
+const (
+	languageJapanese = "ja"
+	languageEnglish  = "en"
+)
+
+// reportMessages レポート・通知の見出し・項目名・定型文の言語ごとの表記（fmtの書式文字列を含む）
+// 項目を追加する場合は、すべての言語に同じキーで追加する
+var reportMessages = map[string]map[string]string{
+	languageJapanese: {
+		"title":                     "SSL証明書有効期限チェック結果",
+		"check_time":                "チェック日時: %s",
+		"sites":                     "サイト数: %d（OK: %d / WARNING: %d / CRITICAL: %d / ERROR: %d）",
+		"sites_html":                `サイト数: %d（<span class="ok">OK: %d</span> / <span class="warning">WARNING: %d</span> / <span class="critical">CRITICAL: %d</span> / <span class="error">ERROR: %d</span>）`,
+		"omitted_ok":                "問題のない証明書（OK）: %d件（表示を省略）",
+		"site_name":                 "サイト名",
+		"url":                       "URL",
+		"issuer":                    "発行者",
+		"san":                       "SAN",
+		"signature_algorithm":       "署名アルゴリズム",
+		"public_key":                "公開鍵",
+		"fingerprint":               "SHA-256フィンガープリント",
+		"serial_number":             "シリアル番号",
+		"tls":                       "TLS",
+		"not_after":                 "有効期限",
+		"days_remaining":            "残り日数",
+		"status":                    "ステータス",
+		"key_bits":                  "%s %dビット",
+		"days":                      "%d日",
+		"self_signed":               " (自己署名)",
+		"chain":                     "証明書チェーン:",
+		"chain_link":                "%s (発行者: %s, 有効期限: %s)",
+		"delta":                     "（前回から%s）",
+		"delta_up":                  "↑%d日、証明書が更新されています",
+		"delta_down":                "↓%d日",
+		"delta_none":                "変化なし",
+		"attention":                 "対応が必要なサイト:",
+		"attention_site":            "- [%s] %s（%s）: %s",
+		"attention_days":            "残り%d日",
+		"see_attachment":            "詳細は添付のHTMLレポートを参照してください。",
+		"muted":                     "（MUTED: %sまで通知を停止中）",
+		"self_signed_label":         "自己署名",
+		"yes":                       "はい",
+		"revocation_status":         "失効状態",
+		"must_staple":               "Must-Staple: はい（OCSPステープル: %s）",
+		"ocsp_stapled":              "あり",
+		"ocsp_not_stapled":          "なし",
+		"subject":                   "主体者",
+		"ext_key_usage":             "拡張キー使用法",
+		"caa":                       "CAA（%s）",
+		"not_before":                "有効期限開始",
+		"not_yet_valid":             "（未発効）",
+		"valid_until":               "有効期限終了",
+		"validity_period":           "有効期間",
+		"expired":                   "（期限切れ）",
+		"stalled":                   "注意: 前回から1日以上経過しても残り日数が減っていません。サーバーやこのホストの時刻を確認してください",
+		"leaf_certificates":         "鍵の種類ごとの証明書:",
+		"leaf_certificate":          "%s %dビット: 有効期限 %s（残り%d日）",
+		"retry":                     "接続: リトライ%d回目で成功",
+		"duration":                  "所要時間",
+		"warning":                   "警告",
+		"error":                     "エラー",
+		"problems":                  "問題",
+		"sites_markdown":            "**サイト数: %d**（✅ OK: %d / ⚠️ WARNING: %d / 🚨 CRITICAL: %d / ❌ ERROR: %d）",
+		"junit_expired":             "証明書の有効期限が切れています（%d日経過）",
+		"junit_remaining":           "証明書の有効期限まで残り%d日です",
+		"app_name":                  "SSL証明書チェッカー",
+		"notification_title":        "SSL証明書有効期限チェック結果（%s）",
+		"title_recovered":           "✅ %s（復旧）",
+		"title_escalated":           "🚨 %s（%s）",
+		"escalation":                "エスカレーション レベル%d: CRITICALが%d回連続",
+		"status_recovered":          "%s、復旧",
+		"pagerduty_summary":         "SSL証明書 %s: %s (%s)",
+		"error_run_deadline":        "実行時間の上限を超えたためチェックできませんでした (run deadline exceeded)",
+		"error_panic":               "チェック中に予期しないエラーが発生しました (panic): %v",
+		"error_client_cert":         "クライアント証明書の読み込みに失敗: %v",
+		"error_fetch":               "証明書の取得に失敗: %v",
+		"error_no_certificate":      "証明書が見つかりません",
+		"error_file":                "証明書ファイルの読み込みに失敗: %v",
+		"problem_tls_version":       "TLSバージョンが古すぎます: %s（最低: %s）",
+		"problem_issuer":            "発行者が想定と異なります: %s（期待: %s）",
+		"problem_not_yet_valid":     "証明書はまだ有効ではありません（有効期限開始: %s）",
+		"problem_fingerprint":       "証明書が変更されています: フィンガープリントが期待値と一致しません（実際: %s）",
+		"problem_mismatch":          "MISMATCH: 証明書はホスト名 %s に対して有効ではありません",
+		"problem_weak_signature":    "弱い署名アルゴリズムが使用されています: %s",
+		"problem_validity_too_long": "証明書の有効期間が長すぎます: %d日（上限%d日）",
+		"problem_no_server_auth":    "拡張キー使用法にserverAuthが含まれていません（%s）",
+		"problem_no_san":            "サブジェクト代替名（SAN）がありません（no SAN）: CommonNameのみの証明書はブラウザで使用できません",
+		"problem_weak_rsa":          "RSA鍵の長さが不足しています: %dビット（最小%dビット）",
+		"problem_weak_ecdsa":        "ECDSA鍵の曲線が弱すぎます: %dビット（最小P-256）",
+		"problem_self_signed":       "自己署名証明書です",
+		"problem_chain":             "証明書チェーンの検証に失敗: %v",
+		"problem_leaf_expired":      "%s証明書の有効期限が切れています（%d日前）",
+		"problem_leaf_remaining":    "%s証明書の有効期限まで残り%d日です",
+		"problem_keytypes":          "鍵の種類ごとの証明書を確認できませんでした（TLS 1.2で接続できないサーバーでは確認できません）: %v",
+		"problem_must_staple":       "証明書にMust-Staple（status_request）が指定されていますが、サーバーがOCSPレスポンスをステープルしていません",
+		"problem_revoked":           "証明書が失効しています (%s)",
+		"problem_caa_unauthorized":  "CAAレコードで許可されていない発行者です: %s（許可: %s）",
+		"caa_none":                  "なし",
+	},
+	languageEnglish: {
+		"title":                     "SSL Certificate Expiry Check Results",
+		"check_time":                "Checked at: %s",
+		"sites":                     "Sites: %d (OK: %d / WARNING: %d / CRITICAL: %d / ERROR: %d)",
+		"sites_html":                `Sites: %d (<span class="ok">OK: %d</span> / <span class="warning">WARNING: %d</span> / <span class="critical">CRITICAL: %d</span> / <span class="error">ERROR: %d</span>)`,
+		"omitted_ok":                "Certificates without problems (OK): %d (omitted)",
+		"site_name":                 "Site",
+		"url":                       "URL",
+		"issuer":                    "Issuer",
+		"san":                       "SAN",
+		"signature_algorithm":       "Signature Algorithm",
+		"public_key":                "Public Key",
+		"fingerprint":               "SHA-256 Fingerprint",
+		"serial_number":             "Serial Number",
+		"tls":                       "TLS",
+		"not_after":                 "Expires",
+		"days_remaining":            "Days Remaining",
+		"status":                    "Status",
+		"key_bits":                  "%s %d bits",
+		"days":                      "%d days",
+		"self_signed":               " (self-signed)",
+		"chain":                     "Certificate chain:",
+		"chain_link":                "%s (issuer: %s, expires: %s)",
+		"delta":                     " (since last check: %s)",
+		"delta_up":                  "↑%d days, certificate renewed",
+		"delta_down":                "↓%d days",
+		"delta_none":                "no change",
+		"attention":                 "Sites requiring attention:",
+		"attention_site":            "- [%s] %s (%s): %s",
+		"attention_days":            "%d days remaining",
+		"see_attachment":            "See the attached HTML report for details.",
+		"muted":                     " (MUTED: notifications paused until %s)",
+		"self_signed_label":         "Self-Signed",
+		"yes":                       "yes",
+		"revocation_status":         "Revocation Status",
+		"must_staple":               "Must-Staple: yes (OCSP staple: %s)",
+		"ocsp_stapled":              "present",
+		"ocsp_not_stapled":          "missing",
+		"subject":                   "Subject",
+		"ext_key_usage":             "Extended Key Usage",
+		"caa":                       "CAA (%s)",
+		"not_before":                "Valid From",
+		"not_yet_valid":             " (not yet valid)",
+		"valid_until":               "Valid Until",
+		"validity_period":           "Validity Period",
+		"expired":                   " (expired)",
+		"stalled":                   "Note: Days remaining have not decreased although at least a day has passed since the last check. Check the clocks of the server and this host",
+		"leaf_certificates":         "Certificates by key type:",
+		"leaf_certificate":          "%s %d bits: expires %s (%d days remaining)",
+		"retry":                     "Connection: succeeded on retry %d",
+		"duration":                  "Duration",
+		"warning":                   "Warning",
+		"error":                     "Error",
+		"problems":                  "Problems",
+		"sites_markdown":            "**Sites: %d** (✅ OK: %d / ⚠️ WARNING: %d / 🚨 CRITICAL: %d / ❌ ERROR: %d)",
+		"junit_expired":             "The certificate expired %d days ago",
+		"junit_remaining":           "The certificate expires in %d days",
+		"app_name":                  "SSL Certificate Checker",
+		"notification_title":        "SSL Certificate Expiry Check Results (%s)",
+		"title_recovered":           "✅ %s (recovered)",
+		"title_escalated":           "🚨 %s (%s)",
+		"escalation":                "Escalation level %d: CRITICAL for %d consecutive runs",
+		"status_recovered":          "%s, recovered",
+		"pagerduty_summary":         "SSL certificate %s: %s (%s)",
+		"error_run_deadline":        "Could not be checked because the run deadline was exceeded (run deadline exceeded)",
+		"error_panic":               "An unexpected error occurred during the check (panic): %v",
+		"error_client_cert":         "Failed to load the client certificate: %v",
+		"error_fetch":               "Failed to retrieve the certificate: %v",
+		"error_no_certificate":      "No certificate found",
+		"error_file":                "Failed to read the certificate file: %v",
+		"problem_tls_version":       "TLS version is too old: %s (minimum: %s)",
+		"problem_issuer":            "Unexpected issuer: %s (expected: %s)",
+		"problem_not_yet_valid":     "The certificate is not yet valid (valid from: %s)",
+		"problem_fingerprint":       "The certificate has changed: fingerprint does not match the expected value (actual: %s)",
+		"problem_mismatch":          "MISMATCH: the certificate is not valid for host name %s",
+		"problem_weak_signature":    "A weak signature algorithm is used: %s",
+		"problem_validity_too_long": "The certificate validity period is too long: %d days (maximum %d days)",
+		"problem_no_server_auth":    "Extended key usage does not include serverAuth (%s)",
+		"problem_no_san":            "The certificate has no subject alternative names (no SAN): browsers do not accept CommonName-only certificates",
+		"problem_weak_rsa":          "RSA key is too short: %d bits (minimum %d bits)",
+		"problem_weak_ecdsa":        "ECDSA curve is too weak: %d bits (minimum P-256)",
+		"problem_self_signed":       "The certificate is self-signed",
+		"problem_chain":             "Certificate chain verification failed: %v",
+		"problem_leaf_expired":      "The %s certificate expired %d days ago",
+		"problem_leaf_remaining":    "The %s certificate expires in %d days",
+		"problem_keytypes":          "Could not check the certificate for each key type (servers that do not accept TLS 1.2 cannot be checked): %v",
+		"problem_must_staple":       "The certificate requires Must-Staple (status_request) but the server did not staple an OCSP response",
+		"problem_revoked":           "The certificate has been revoked (%s)",
+		"problem_caa_unauthorized":  "The issuer is not authorized by the CAA records: %s (authorized: %s)",
+		"caa_none":                  "none",
+	},
+}
+
+// reportMessage 指定した言語の表記を返す（未対応の言語やキーの場合は日本語の表記）
+func reportMessage(lang, key string) string {
+	if message, ok := reportMessages[lang][key]; ok {
+		return message
+	}
+	return reportMessages[languageJapanese][key]
+}
+
+// reportMessagef 指定した言語の表記に引数を埋め込む（引数がない場合は表記をそのまま返す）
+func reportMessagef(lang, key string, args ...any) string {
+	if len(args) == 0 {
+		return reportMessage(lang, key)
+	}
+	return fmt.Sprintf(reportMessage(lang, key), args...)
+}
+
+// reportLanguage レポートの言語を返す（省略時は日本語）
+func (c *Config) reportLanguage() string {
+	if c.Report.Language == "" {
+		return languageJapanese
+	}
+	return c.Report.Language
+}
+
+// logLanguage ログのメッセージの言語を返す（省略時は日本語）
+func (c *Config) logLanguage() string {
+	if c.Logging.Language == "" {
+		return languageJapanese
+	}
+	return c.Logging.Language
+}
+
+// message レポートの言語での表記を返す
+func (c *Config) message(key string) string {
+	return reportMessage(c.reportLanguage(), key)
+}
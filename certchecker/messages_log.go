@@ -0,0 +1,114 @@
+package certchecker
+
+// logMessages ログの書式文字列（日本語）に対応する英語の表記（logging.language: en の場合に使用）
+// ログを追加・変更する場合は、日本語の書式文字列をキーとして英語の表記を追加する
+var logMessages = map[string]string{
+	// サイトのチェック
+	"%dサイトのチェックを開始します":               "Starting checks for %d sites",
+	"チェック開始: %s (%s:%d)":             "Check started: %s (%s:%d)",
+	"チェック完了: %s (%s)":                "Check finished: %s (%s)",
+	"%s のチェック中にpanicが発生しました: %v\n%s": "Panic while checking %s: %v\n%s",
+	"実行時間の上限（%d秒）を超えたため、完了していないサイトのチェックを中断しました":              "Stopped the checks of unfinished sites because the run exceeded the time limit (%d seconds)",
+	"すべてのサイトのチェックが完了しました":                                    "Finished checking all sites",
+	"sites[%d] は sites[%d] と接続先（%s）が重複しているため除外します":           "Skipping sites[%[1]d] because it has the same target (%[3]s) as sites[%[2]d]",
+	"%s - 接続に失敗したため%v後にリトライします (%d/%d): %v":                  "%s - Connection failed, retrying in %v (%d/%d): %v",
+	"チェックを中断したため、レポートの出力と通知を行いません":                           "The checks were interrupted, so no reports or notifications will be sent",
+	"%s - %s証明書はありません: %v":                                   "%s - No %s certificate: %v",
+	"%s - %s証明書を取得できませんでした: %v":                              "%s - Could not get the %s certificate: %v",
+	"%s:%d - CAAレコードの取得に失敗: %s: %v":                          "%s:%d - Failed to look up CAA records: %s: %v",
+	"%s:%d - CAAレコードがないため、どのCAでも発行できます":                      "%s:%d - No CAA records, so any CA may issue certificates",
+	"%s:%d - 発行者（%s）に対応するCAAの識別子が不明なため、CAAレコードによる確認をスキップします": "%s:%d - Skipping the CAA check because the CAA identifier for the issuer (%s) is unknown",
+	"%s:%d - OCSPレスポンダーが指定されていないため失効確認をスキップします":              "%s:%d - Skipping the revocation check because no OCSP responder is specified",
+	"%s:%d - 発行者の証明書が提示されていないためOCSPによる失効確認をスキップします":          "%s:%d - Skipping the OCSP revocation check because the issuer certificate was not presented",
+	"%s:%d - OCSPによる失効確認に失敗: %v":                             "%s:%d - OCSP revocation check failed: %v",
+	"%s:%d - CRL配布ポイントが指定されていないため失効確認をスキップします":               "%s:%d - Skipping the revocation check because no CRL distribution point is specified",
+	"%s:%d - 発行者の証明書が提示されていないためCRLによる失効確認をスキップします":           "%s:%d - Skipping the CRL revocation check because the issuer certificate was not presented",
+	"%s:%d - CRLによる失効確認に失敗: %v":                              "%s:%d - CRL revocation check failed: %v",
+	"%s: mute_untilの解析に失敗したため、ミュートせずに通知します: %v":              "%s: Failed to parse mute_until, notifying without muting: %v",
+	"include: %s に一致するファイルがありません":                            "include: No files match %s",
+
+	// レポート・履歴
+	"テキスト": "Text",
+	"%sレポートの書き出しに失敗しました: %v":                  "Failed to write the %s report: %v",
+	"%sレポートを書き出しました: %s":                      "Wrote the %s report: %s",
+	"独自のテンプレートでのレポート生成に失敗したため標準の形式で出力します: %v": "Failed to generate the report with the custom template, using the standard format: %v",
+	"テキストレポートの生成に失敗: %v":                      "Failed to generate the text report: %v",
+	"JSONレポートの生成に失敗: %v":                      "Failed to generate the JSON report: %v",
+	"JUnitレポートの生成に失敗: %v":                     "Failed to generate the JUnit report: %v",
+	"メトリクスファイルの書き出しに失敗しました: %v":               "Failed to write the metrics file: %v",
+	"メトリクスファイルを書き出しました: %s":                   "Wrote the metrics file: %s",
+	"履歴の読み込みに失敗しました: %v":                      "Failed to read the history: %v",
+	"履歴の保存に失敗しました: %v":                        "Failed to save the history: %v",
+	"履歴を保存しました: %s":                           "Saved the history: %s",
+
+	// 通知
+	"メール": "Email",
+	"メール送信の対象となるサイトがありません":                 "No sites to send by email",
+	"メール送信に失敗しました: %v":                     "Failed to send email: %v",
+	"メールを送信しました":                           "Sent the email",
+	"メール送信は無効です":                           "Email is disabled",
+	"メールの送信に失敗したため%v後にリトライします (%d/%d): %v": "Failed to send email, retrying in %v (%d/%d): %v",
+	"Discord通知":   "Discord notification",
+	"Slack通知":     "Slack notification",
+	"Teams通知":     "Teams notification",
+	"Telegram通知":  "Telegram notification",
+	"Webhook通知":   "Webhook notification",
+	"PagerDuty連携": "PagerDuty integration",
+	"%sの対象となるサイトがありません":                           "No sites for %s",
+	"%sでエラーが発生しました: %v":                           "%s failed: %v",
+	"[dry-run] %sに%d件の結果を通知します（送信はしません）":          "[dry-run] Would send %[2]d results to %[1]s (not sent)",
+	"状態ファイルの読み込みに失敗しました。すべてのサイトを通知対象とします: %v":     "Failed to read the state file, notifying all sites: %v",
+	"ミュート中のため通知しないサイト: %d件":                       "Sites not notified because they are muted: %d",
+	"前回から状態が変化したサイト: %d件":                         "Sites whose status changed since the last run: %d",
+	"クールダウンファイルの読み込みに失敗しました: %v":                  "Failed to read the cooldown file: %v",
+	"通知対象のサイトがないため通知を送信しません":                      "No sites to notify, so no notifications will be sent",
+	"通知の送信に失敗したため、次回の実行で再通知するサイト: %d件":            "Sites to notify again in the next run because the notification failed: %d",
+	"クールダウンファイルの書き込みに失敗しました: %v":                  "Failed to write the cooldown file: %v",
+	"状態ファイルの書き込みに失敗しました: %v":                      "Failed to write the state file: %v",
+	"%s - 静穏時間帯のため通知を見送ります (%s)":                  "%s - Not notifying during quiet hours (%s)",
+	"%s - クールダウン期間内のため通知しません (%s)":                "%s - Not notifying during the cooldown period (%s)",
+	"%s - CRITICALが%d回連続しているためエスカレーションします（レベル%d）": "%s - Escalating because the site has been CRITICAL %d times in a row (level %d)",
+	"Discord通知は無効です":                              "Discord notifications are disabled",
+	"Discord Webhook URLが設定されていません":               "Discord webhook URL is not set",
+	"Discord通知対象の結果がありません":                        "No results to notify to Discord",
+	"Discord通知の送信に失敗しました (%s): %v":                "Failed to send the Discord notification (%s): %v",
+	"Discord通知を送信しました (%s)":                       "Sent the Discord notification (%s)",
+	"Discordのレート制限を受けたため%v後に再送します (%d/%d)":        "Rate limited by Discord, resending in %v (%d/%d)",
+	"Slack通知は無効です":                                "Slack notifications are disabled",
+	"Slack Webhook URLが設定されていません":                 "Slack webhook URL is not set",
+	"Slack通知対象の結果がありません":                          "No results to notify to Slack",
+	"Slack通知を送信しました":                              "Sent the Slack notification",
+	"Teams通知は無効です":                                "Teams notifications are disabled",
+	"Teams Webhook URLが設定されていません":                 "Teams webhook URL is not set",
+	"Teams通知対象の結果がありません":                          "No results to notify to Teams",
+	"Teams通知を送信しました":                              "Sent the Teams notification",
+	"Telegram通知は無効です":                             "Telegram notifications are disabled",
+	"TelegramのボットトークンまたはチャットIDが設定されていません":         "Telegram bot token or chat ID is not set",
+	"Telegram通知対象の結果がありません":                       "No results to notify to Telegram",
+	"Telegram通知を送信しました":                           "Sent the Telegram notification",
+	"Webhook通知は無効です":                              "Webhook notifications are disabled",
+	"Webhook URLが設定されていません":                       "Webhook URL is not set",
+	"Webhook通知対象の結果がありません":                        "No results to notify to the webhook",
+	"Webhook通知を送信しました":                            "Sent the webhook notification",
+	"PagerDuty連携は無効です":                            "PagerDuty integration is disabled",
+	"PagerDutyのルーティングキーが設定されていません":                "PagerDuty routing key is not set",
+	"PagerDutyにイベントを送信しました（発生: %d件、解決: %d件）":      "Sent events to PagerDuty (triggered: %d, resolved: %d)",
+
+	// 起動・常駐
+	"SSL証明書チェッカーを開始します":                           "Starting the SSL certificate checker",
+	"SSL証明書チェッカーを終了します":                           "Exiting the SSL certificate checker",
+	"ドライランのため通知は送信しません":                           "Dry run, no notifications will be sent",
+	"メトリクスの公開に失敗しました: %v":                         "Failed to serve metrics: %v",
+	"スケジュールの解析に失敗しました: %v":                        "Failed to parse the schedule: %v",
+	"ログファイルのオープンに失敗: %v":                          "Failed to open the log file: %v",
+	"syslogへの接続に失敗したため、ファイルまたは標準出力にログを出力します: %v":  "Failed to connect to syslog, logging to the file or standard output instead: %v",
+	"常駐モードで起動しました（チェック間隔: %v）":                    "Started in daemon mode (check interval: %v)",
+	"停止要求を受け付けたため常駐モードを終了します":                     "Received a stop request, exiting daemon mode",
+	"%d回目のチェックを開始します":                             "Starting check #%d",
+	"%d回目のチェックが完了しました（所要時間: %v）":                  "Finished check #%d (took %v)",
+	"スケジュールモードで起動しました（次回: %s）":                    "Started in schedule mode (next run: %s)",
+	"停止要求を受け付けたためスケジュールモードを終了します":                 "Received a stop request, exiting schedule mode",
+	"次回のチェック: %s":                                 "Next check: %s",
+	"メトリクスを公開します: http://%s/metrics (チェック間隔: %v)": "Serving metrics at http://%s/metrics (check interval: %v)",
+	"停止要求を受け付けたためメトリクスの公開を終了します":                  "Received a stop request, no longer serving metrics",
+}
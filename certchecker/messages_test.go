@@ -0,0 +1,259 @@
+package certchecker
+
+import (
+	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// containsJapanese 文字列にひらがな・カタカナ・漢字が含まれるかを判定
+func containsJapanese(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han)
+	}) >= 0
+}
+
+// TestReportMessagesComplete すべての言語に同じ項目の表記があることのテスト
+func TestReportMessagesComplete(t *testing.T) {
+	for lang, messages := range reportMessages {
+		for key := range reportMessages[languageJapanese] {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%s に %s の表記がありません", lang, key)
+			}
+		}
+		for key := range messages {
+			if _, ok := reportMessages[languageJapanese][key]; !ok {
+				t.Errorf("%s の %s は日本語にない項目です", lang, key)
+			}
+		}
+	}
+}
+
+// TestGenerateReportsEnglish report.languageにenを指定した場合に英語でレポートを生成するテスト
+func TestGenerateReportsEnglish(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	delta := -1
+	results := []CertInfo{
+		{
+			SiteName: "Example", URL: "example.com", Port: 443, Status: "WARNING", DaysRemaining: 20,
+			Issuer: "Example CA", KeyType: "ECDSA", KeyBits: 256, SelfSigned: true,
+			NotAfter: time.Now().AddDate(0, 0, 20), DaysRemainingDelta: &delta,
+		},
+		{SiteName: "Broken", URL: "broken.example.com", Port: 443, Status: "ERROR", ErrorMessage: "connection refused"},
+	}
+
+	testCases := []struct {
+		name     string
+		language string
+		expected []string
+		absent   []string
+	}{
+		{
+			name:     "英語",
+			language: "en",
+			expected: []string{"Status", "Days Remaining", "Issuer", "20 days", "↓1 days"},
+			absent:   []string{"ステータス", "残り日数", "20日", "自己署名"},
+		},
+		{
+			name:     "省略時は日本語",
+			language: "",
+			expected: []string{"ステータス", "残り日数", "発行者", "20日", "↓1日", "自己署名"},
+			absent:   []string{"Days Remaining"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{}
+			config.Report.Language = tc.language
+
+			reports := map[string]string{
+				"テキストレポート": GenerateTextReport(config, results),
+				"HTMLレポート": GenerateHTMLReport(config, results),
+			}
+			for name, report := range reports {
+				for _, expected := range tc.expected {
+					if !strings.Contains(report, expected) {
+						t.Errorf("%sに %q が含まれていません:\n%s", name, expected, report)
+					}
+				}
+				for _, absent := range tc.absent {
+					if strings.Contains(report, absent) {
+						t.Errorf("%sに %q が含まれています", name, absent)
+					}
+				}
+			}
+		})
+	}
+
+	config := &Config{}
+	config.Report.Language = "en"
+	summary := emailSummaryText(config, results)
+	for _, expected := range []string{"Sites requiring attention:", "- [WARNING] Example (example.com:443): 20 days remaining", "- [ERROR] Broken (broken.example.com:443): connection refused"} {
+		if !strings.Contains(summary, expected) {
+			t.Errorf("メールの本文に %q が含まれていません:\n%s", expected, summary)
+		}
+	}
+}
+
+// TestTextReportTemplateMessage テンプレートのmsgでreport.languageの表記を出力できることのテスト
+func TestTextReportTemplateMessage(t *testing.T) {
+	tmpl := template.Must(template.New("text").Funcs(textReportFuncs).Parse(`{{msg "days_remaining"}}: {{msg "days" 20}}`))
+
+	testCases := []struct {
+		language string
+		expected string
+	}{
+		{language: "", expected: "残り日数: 20日"},
+		{language: "en", expected: "Days Remaining: 20 days"},
+	}
+	for _, tc := range testCases {
+		config := &Config{}
+		config.Report.Language = tc.language
+		report, err := renderTextReport(tmpl, textReportData{}, config, false)
+		if err != nil {
+			t.Fatalf("テンプレートの実行に失敗: %v", err)
+		}
+		if report != tc.expected {
+			t.Errorf("言語 %q 期待: %q, 実際: %q", tc.language, tc.expected, report)
+		}
+	}
+}
+
+// TestEvaluateCertificateEnglish report.languageにenを指定した場合に証明書の問題を英語で表示するテスト
+func TestEvaluateCertificateEnglish(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "www.example.com"},
+		DNSNames: []string{"www.example.com"},
+	}, nil)
+
+	testCases := []struct {
+		language string
+		expected []string
+	}{
+		{language: "en", expected: []string{"MISMATCH: the certificate is not valid for host name a.example", "The certificate is self-signed"}},
+		{language: "", expected: []string{"MISMATCH: 証明書はホスト名 a.example に対して有効ではありません", "自己署名証明書です"}},
+	}
+	for _, tc := range testCases {
+		config := &Config{}
+		config.Alert.WarningDays = 30
+		config.Alert.CriticalDays = 7
+		config.Alert.FlagSelfSigned = true
+		config.Report.Language = tc.language
+
+		info := evaluateCertificate(context.Background(), config, Site{Name: "Example", URL: "a.example"}, []*x509.Certificate{cert.cert}, 1)
+		for _, expected := range tc.expected {
+			if !strings.Contains(info.ErrorMessage, expected) {
+				t.Errorf("言語 %q: %q が含まれていません: %s", tc.language, expected, info.ErrorMessage)
+			}
+		}
+		if tc.language == "en" && containsJapanese(info.ErrorMessage) {
+			t.Errorf("英語の問題の表示に日本語が含まれています: %s", info.ErrorMessage)
+		}
+
+		report := GenerateTextReport(config, []CertInfo{info})
+		if tc.language == "en" && containsJapanese(report) {
+			t.Errorf("英語のレポートに日本語が含まれています:\n%s", report)
+		}
+	}
+}
+
+// TestNotificationsEnglish report.languageにenを指定した場合に各通知を英語で送信するテスト
+func TestNotificationsEnglish(t *testing.T) {
+	var body string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	originalTelegram, originalPagerDuty := telegramAPIBase, pagerDutyEventsURL
+	telegramAPIBase, pagerDutyEventsURL = server.URL, server.URL
+	defer func() { telegramAPIBase, pagerDutyEventsURL = originalTelegram, originalPagerDuty }()
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{
+			SiteName: "Critical Site", URL: "critical.example.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3,
+			Issuer: "Example CA", NotAfter: time.Now().AddDate(0, 0, 3), SelfSigned: true,
+			ErrorMessage: "The certificate is self-signed", CriticalRuns: 3, EscalationLevel: 1,
+		},
+		{SiteName: "Recovered Site", URL: "ok.example.com", Port: 443, Status: "OK", DaysRemaining: 80, Recovered: true},
+		{SiteName: "Broken Site", URL: "broken.example.com", Port: 443, Status: "ERROR", ErrorMessage: "connection refused"},
+	}
+
+	testCases := []struct {
+		name     string
+		status   int
+		setup    func(config *Config)
+		send     func(context.Context, *Config, []CertInfo) error
+		expected []string
+	}{
+		{"Discord", http.StatusNoContent, func(c *Config) {
+			c.Discord.Enabled, c.Discord.WebhookURL = true, server.URL
+		}, SendDiscordNotification, []string{"SSL Certificate Checker", "Days Remaining", "3 days", "Self-Signed", "Escalation level 1: CRITICAL for 3 consecutive runs"}},
+		{"Slack", http.StatusOK, func(c *Config) {
+			c.Slack.Enabled, c.Slack.WebhookURL = true, server.URL
+		}, SendSlackNotification, []string{"SSL Certificate Checker", "SSL Certificate Expiry Check Results", "*Days Remaining*", "*Error*"}},
+		{"Teams", http.StatusOK, func(c *Config) {
+			c.Teams.Enabled, c.Teams.WebhookURL = true, server.URL
+		}, SendTeamsNotification, []string{"SSL Certificate Expiry Check Results", "Days Remaining", "Warning", "Error"}},
+		{"Telegram", http.StatusOK, func(c *Config) {
+			c.Telegram.Enabled, c.Telegram.BotToken, c.Telegram.ChatID = true, "token", "chat"
+		}, SendTelegramNotification, []string{"SSL Certificate Expiry Check Results", "Days Remaining: 3 days", "Error: connection refused"}},
+		{"PagerDuty", http.StatusAccepted, func(c *Config) {
+			c.PagerDuty.Enabled, c.PagerDuty.RoutingKey = true, "key"
+		}, SendPagerDutyAlert, []string{"SSL certificate CRITICAL: Critical Site (critical.example.com:443)"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{}
+			config.Report.Language = "en"
+			tc.setup(config)
+			status = tc.status
+			body = ""
+
+			// PagerDutyは1件ずつ送信するため、最初のサイトのイベントを確認する
+			sendResults := results
+			if tc.name == "PagerDuty" {
+				sendResults = results[:1]
+			}
+			if err := tc.send(context.Background(), config, sendResults); err != nil {
+				t.Fatalf("通知でエラーが発生しました: %v", err)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(body, expected) {
+					t.Errorf("%q が含まれていません:\n%s", expected, body)
+				}
+			}
+			if containsJapanese(body) {
+				t.Errorf("英語の通知に日本語が含まれています:\n%s", body)
+			}
+		})
+	}
+
+	config := &Config{}
+	config.Report.Language = "en"
+	if title := notificationTitle(config, results[1]); title != "✅ Recovered Site (recovered)" {
+		t.Errorf("復旧したサイトのタイトルが正しくありません: %s", title)
+	}
+}
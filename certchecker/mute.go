@@ -0,0 +1,46 @@
+package certchecker
+
+import (
+	"time"
+)
+
+// muteDateFormat mute_untilの日付の形式
+const muteDateFormat = "2006-01-02"
+
+// parseMuteUntil mute_untilの日付を解析し、ミュートが終了する時刻（指定した日の翌日0時）を返す
+func parseMuteUntil(value string, loc *time.Location) (time.Time, error) {
+	date, err := time.ParseInLocation(muteDateFormat, value, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return date.AddDate(0, 0, 1), nil
+}
+
+// applyMute mute_untilの期間内であれば、サイトの結果にミュート中であることを記録する
+// 指定した日の終わり（report.timezoneの日付）まではチェックを行うが通知はせず、期間が過ぎれば自動的に通常どおり通知する
+func applyMute(config *Config, site Site, info *CertInfo, now time.Time) {
+	if site.MuteUntil == "" {
+		return
+	}
+	end, err := parseMuteUntil(site.MuteUntil, config.reportLocation())
+	if err != nil {
+		// ValidateConfigで確認済みのため、ここには到達しない
+		LogWarnf("%s: mute_untilの解析に失敗したため、ミュートせずに通知します: %v", info.SiteName, err)
+		return
+	}
+	if now.Before(end) {
+		info.Muted = true
+		info.MutedUntil = site.MuteUntil
+	}
+}
+
+// unmutedResults ミュート中のサイトを除いた結果を返す
+func unmutedResults(results []CertInfo) []CertInfo {
+	unmuted := make([]CertInfo, 0, len(results))
+	for _, result := range results {
+		if !result.Muted {
+			unmuted = append(unmuted, result)
+		}
+	}
+	return unmuted
+}
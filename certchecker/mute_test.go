@@ -0,0 +1,112 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestApplyMute mute_untilの期間の判定のテスト
+func TestApplyMute(t *testing.T) {
+	config := &Config{}
+	config.location = time.UTC
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		muteUntil string
+		expected  bool
+	}{
+		{name: "指定なし", muteUntil: "", expected: false},
+		{name: "未来の日付", muteUntil: "2026-10-31", expected: true},
+		{name: "当日（日の終わりまでミュート）", muteUntil: "2026-10-16", expected: true},
+		{name: "過去の日付", muteUntil: "2026-10-15", expected: false},
+	}
+
+	for _, tc := range testCases {
+		info := CertInfo{SiteName: tc.name, Status: "CRITICAL"}
+		applyMute(config, Site{Name: tc.name, MuteUntil: tc.muteUntil}, &info, now)
+		if info.Muted != tc.expected {
+			t.Errorf("%s: ミュートの判定が正しくありません。期待: %v, 実際: %v", tc.name, tc.expected, info.Muted)
+		}
+		if info.Muted && info.MutedUntil != tc.muteUntil {
+			t.Errorf("%s: ミュートの期限が正しくありません。期待: %s, 実際: %s", tc.name, tc.muteUntil, info.MutedUntil)
+		}
+	}
+}
+
+// TestDispatchNotificationsMuted ミュート中のサイトを通知せず、期限が過ぎたサイトは通常どおり通知するテスト
+func TestDispatchNotificationsMuted(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// チェック自体は行われ、証明書の期限が近い結果になる
+	original := checkSite
+	t.Cleanup(func() { checkSite = original })
+	checkSite = func(ctx context.Context, config *Config, site Site) CertInfo {
+		return CertInfo{SiteName: site.Name, URL: site.URL, Port: 443, Status: "CRITICAL", DaysRemaining: 3}
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	today := time.Now().In(JST)
+	config := &Config{Sites: []Site{
+		{URL: "renewing.example.com", Name: "Renewing", MuteUntil: today.AddDate(0, 0, 7).Format("2006-01-02")},
+		{URL: "expired-mute.example.com", Name: "ExpiredMute", MuteUntil: today.AddDate(0, 0, -1).Format("2006-01-02")},
+	}}
+	config.Discord.Enabled = true
+	config.Discord.WebhookURL = server.URL
+	config.StateFile = filepath.Join(t.TempDir(), "state.json")
+
+	results := CheckAllSites(context.Background(), config)
+	if !results[0].Muted || results[0].Status != "CRITICAL" {
+		t.Errorf("ミュート中のサイトの結果が正しくありません。ミュート: %v, ステータス: %s", results[0].Muted, results[0].Status)
+	}
+	if results[1].Muted {
+		t.Error("期限が過ぎたミュートが有効になっています")
+	}
+	if report := GenerateTextReport(config, results); !strings.Contains(report, "MUTED") {
+		t.Errorf("テキストレポートにミュート中であることが表示されていません:\n%s", report)
+	}
+
+	DispatchNotifications(context.Background(), config, results)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("送信回数が正しくありません。期待: 1, 実際: %d", len(bodies))
+	}
+	if strings.Contains(bodies[0], "Renewing") {
+		t.Error("ミュート中のサイトが通知されました")
+	}
+	if !strings.Contains(bodies[0], "ExpiredMute") {
+		t.Error("ミュートの期限が過ぎたサイトが通知されていません")
+	}
+
+	// ミュート中のサイトは状態ファイルに記録せず、ミュートが終わった後に通知される
+	state, err := loadState(config.StateFile)
+	if err != nil {
+		t.Fatalf("状態ファイルの読み込みに失敗: %v", err)
+	}
+	if _, ok := state[stateKey(results[0])]; ok {
+		t.Error("ミュート中のサイトの状態が記録されています")
+	}
+	if _, ok := state[stateKey(results[1])]; !ok {
+		t.Error("ミュートの期限が過ぎたサイトの状態が記録されていません")
+	}
+}
@@ -0,0 +1,293 @@
+package certchecker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// notifyClient 通知の送信に使用するHTTPクライアント
+// デフォルトのクライアントにはタイムアウトがないため、応答しないエンドポイントで実行全体が止まらないようにする
+// プロキシは環境変数（HTTPS_PROXY、HTTP_PROXY、NO_PROXY）に従う
+var notifyClient = &http.Client{Timeout: defaultTimeout, Transport: newNotifyTransport(nil)}
+
+// newNotifyTransport 通知用のTransportを作成する（proxyURLがnilの場合は環境変数のプロキシ設定を使用する）
+func newNotifyTransport(proxyURL *url.URL) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return transport
+}
+
+// parseProxyURL proxy_urlを解析する（http、https、socks5のスキームとホスト名が必要）
+func parseProxyURL(raw string) (*url.URL, error) {
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("未対応のスキームです: %q（http、https、socks5 のいずれかを指定してください）", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("ホスト名が指定されていません: %s", raw)
+	}
+	return proxyURL, nil
+}
+
+// notifyHTTPClient 通知用のHTTPクライアントを返す
+// タイムアウト（秒）を指定した場合（0の場合はデフォルト）やproxy_urlを設定した場合は、共通のクライアントを複製して変更する
+func notifyHTTPClient(config *Config, timeout int) *http.Client {
+	if timeout <= 0 && config.notifyTransport == nil {
+		return notifyClient
+	}
+	client := *notifyClient
+	if timeout > 0 {
+		client.Timeout = time.Duration(timeout) * time.Second
+	}
+	if config.notifyTransport != nil {
+		client.Transport = config.notifyTransport
+	}
+	return &client
+}
+
+// postJSON JSONをPOSTで送信する（ctxがキャンセルされた場合は送信を中断する）
+func postJSON(ctx context.Context, client *http.Client, url string, data []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return client.Do(req)
+}
+
+// filterByStatus notify_onに含まれるステータスの結果だけを返す（notify_onが空の場合はすべての結果）
+// 復旧したサイトは、通知対象のステータスに関係なく含める
+func filterByStatus(results []CertInfo, notifyOn []string) []CertInfo {
+	if len(notifyOn) == 0 {
+		return results
+	}
+
+	filteredResults := []CertInfo{}
+	for _, result := range results {
+		if result.Recovered {
+			filteredResults = append(filteredResults, result)
+			continue
+		}
+		for _, status := range notifyOn {
+			if result.Status == status {
+				filteredResults = append(filteredResults, result)
+				break
+			}
+		}
+	}
+	return filteredResults
+}
+
+// notificationTitle 通知に表示するサイトのタイトルを作成（復旧したサイトはその旨を表示する）
+func notificationTitle(config *Config, cert CertInfo) string {
+	if cert.Recovered {
+		return fmt.Sprintf(config.message("title_recovered"), cert.SiteName)
+	}
+	if cert.EscalationLevel > 0 {
+		return fmt.Sprintf(config.message("title_escalated"), cert.SiteName, escalationLabel(config, cert))
+	}
+	return fmt.Sprintf("🔒 %s", cert.SiteName)
+}
+
+// worstStatus 結果の中で最も深刻なステータスを返す（結果がない場合はOK）
+func worstStatus(results []CertInfo) string {
+	worst := "OK"
+	for _, result := range results {
+		if statusSeverity[result.Status] > statusSeverity[worst] {
+			worst = result.Status
+		}
+	}
+	return worst
+}
+
+// notificationChannels サイトのnotify_channelsで指定できる通知先
+var notificationChannels = []string{"email", "discord", "slack", "teams", "telegram", "webhook", "pagerduty"}
+
+// routeResults 指定した通知先に送信する結果を返す
+// notify_channelsを省略したサイトは、有効なすべての通知先に送信する
+func routeResults(results []CertInfo, channel string) []CertInfo {
+	routed := make([]CertInfo, 0, len(results))
+	for _, result := range results {
+		if result.NotifyChannels == nil || slices.Contains(result.NotifyChannels, channel) {
+			routed = append(routed, result)
+		}
+	}
+	return routed
+}
+
+// SendNotifications 有効なすべての通知先に結果を送信し、送信に失敗した通知先に含まれていた結果を返す
+// 各通知先には、サイトのnotify_channelsでその通知先が指定された（または省略された）結果だけを送信する
+// 送信に失敗した通知先があっても、残りの通知先への送信は続ける
+func SendNotifications(ctx context.Context, config *Config, results []CertInfo) (failed []CertInfo) {
+	if config.DryRun {
+		logDryRun(config, results)
+		return nil
+	}
+
+	failedKeys := map[string]bool{}
+	markFailed := func(routed []CertInfo) {
+		for _, result := range routed {
+			failedKeys[stateKey(result)] = true
+		}
+	}
+
+	// メール送信
+	if config.Email.Enabled {
+		if emailResults := routeResults(results, "email"); len(emailResults) == 0 {
+			LogDebugf("メール送信の対象となるサイトがありません")
+		} else if err := SendEmail(ctx, config, emailResults); err != nil {
+			LogErrorf("メール送信に失敗しました: %v", err)
+			markFailed(emailResults)
+		} else {
+			LogInfof("メールを送信しました")
+		}
+	} else {
+		LogDebugf("メール送信は無効です")
+	}
+
+	channels := []struct {
+		channel string
+		name    string
+		send    func(context.Context, *Config, []CertInfo) error
+	}{
+		{"discord", logMessagef("Discord通知"), SendDiscordNotification},
+		{"slack", logMessagef("Slack通知"), SendSlackNotification},
+		{"teams", logMessagef("Teams通知"), SendTeamsNotification},
+		{"telegram", logMessagef("Telegram通知"), SendTelegramNotification},
+		{"webhook", logMessagef("Webhook通知"), SendWebhookNotification},
+		{"pagerduty", logMessagef("PagerDuty連携"), SendPagerDutyAlert},
+	}
+	for _, channel := range channels {
+		routed := routeResults(results, channel.channel)
+		if len(routed) == 0 {
+			LogDebugf("%sの対象となるサイトがありません", channel.name)
+			continue
+		}
+		if err := channel.send(ctx, config, routed); err != nil {
+			LogErrorf("%sでエラーが発生しました: %v", channel.name, err)
+			markFailed(routed)
+		}
+	}
+
+	for _, result := range results {
+		if failedKeys[stateKey(result)] {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// excludeResults resultsからexcludeに含まれるサイトの結果を除く
+func excludeResults(results, exclude []CertInfo) []CertInfo {
+	excluded := make(map[string]bool, len(exclude))
+	for _, result := range exclude {
+		excluded[stateKey(result)] = true
+	}
+	kept := make([]CertInfo, 0, len(results))
+	for _, result := range results {
+		if !excluded[stateKey(result)] {
+			kept = append(kept, result)
+		}
+	}
+	return kept
+}
+
+// logDryRun ドライランで、実際には送信せずに各通知先へ送信する予定の件数をログに記録する
+func logDryRun(config *Config, results []CertInfo) {
+	channels := []struct {
+		name     string
+		channel  string
+		enabled  bool
+		notifyOn []string
+	}{
+		{logMessagef("メール"), "email", config.Email.Enabled, nil},
+		{"Discord", "discord", config.Discord.Enabled, config.Discord.NotifyOn},
+		{"Slack", "slack", config.Slack.Enabled, config.Slack.NotifyOn},
+		{"Teams", "teams", config.Teams.Enabled, config.Teams.NotifyOn},
+		{"Telegram", "telegram", config.Telegram.Enabled, config.Telegram.NotifyOn},
+		{"Webhook", "webhook", config.Webhook.Enabled, config.Webhook.NotifyOn},
+		{"PagerDuty", "pagerduty", config.PagerDuty.Enabled, nil},
+	}
+	for _, channel := range channels {
+		if !channel.enabled {
+			continue
+		}
+		routed := routeResults(results, channel.channel)
+		LogInfof("[dry-run] %sに%d件の結果を通知します（送信はしません）", channel.name, len(filterByStatus(routed, channel.notifyOn)))
+	}
+}
+
+// DispatchNotifications 状態ファイルとクールダウンの設定に従って通知対象を絞り込み、通知を送信する
+func DispatchNotifications(ctx context.Context, config *Config, results []CertInfo) {
+	// 状態ファイルを使用する場合は、前回の状態からCRITICALが続いているサイトをエスカレーションする
+	var previous map[string]siteState
+	if config.StateFile != "" {
+		var err error
+		previous, err = loadState(config.StateFile)
+		if err != nil {
+			LogWarnf("状態ファイルの読み込みに失敗しました。すべてのサイトを通知対象とします: %v", err)
+		}
+		applyEscalation(config, results, previous)
+	}
+	// ミュート中のサイトはレポートには含めるが、通知の対象からは除く
+	notifyResults := unmutedResults(results)
+	if muted := len(results) - len(notifyResults); muted > 0 {
+		LogInfof("ミュート中のため通知しないサイト: %d件", muted)
+	}
+
+	// 状態ファイルを使用する場合は、前回から状態が変化したサイトだけを通知する
+	if config.StateFile != "" {
+		notifyResults = changedResults(notifyResults, previous)
+		LogInfof("前回から状態が変化したサイト: %d件", len(notifyResults))
+	}
+
+	// 静穏時間帯はCRITICAL・ERROR以外のサイトの通知を見送り、クールダウン期間内に同じステータスで通知済みのサイトは通知しない
+	tracker, err := loadNotifyTracker(config)
+	if err != nil {
+		LogWarnf("クールダウンファイルの読み込みに失敗しました: %v", err)
+	}
+	notifyResults, deferred := tracker.filter(notifyResults)
+
+	var failed []CertInfo
+	if len(notifyResults) > 0 {
+		failed = SendNotifications(ctx, config, notifyResults)
+	} else {
+		LogInfof("通知対象のサイトがないため通知を送信しません")
+	}
+
+	// ドライランでは実際に通知していないため、次回の通知に影響しないよう記録を更新しない
+	if config.DryRun {
+		return
+	}
+
+	// 送信に失敗したサイトは通知済みとして記録せず、次回の実行で再び通知する
+	if len(failed) > 0 {
+		LogWarnf("通知の送信に失敗したため、次回の実行で再通知するサイト: %d件", len(failed))
+	}
+
+	if tracker.window > 0 {
+		tracker.markNotified(excludeResults(notifyResults, failed))
+		if err := tracker.save(); err != nil {
+			LogErrorf("クールダウンファイルの書き込みに失敗しました: %v", err)
+		}
+	}
+
+	if config.StateFile != "" {
+		if err := saveState(config.StateFile, results, previous, append(deferred, failed...)); err != nil {
+			LogErrorf("状態ファイルの書き込みに失敗しました: %v", err)
+		}
+	}
+}
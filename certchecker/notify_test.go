@@ -0,0 +1,400 @@
+package certchecker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TestSendDiscordNotificationTimeout 応答しないWebhookでタイムアウトするかのテスト
+func TestSendDiscordNotificationTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	config := &Config{}
+	config.Discord.Enabled = true
+	config.Discord.WebhookURL = server.URL
+	config.Discord.Timeout = 1
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+	}
+
+	start := time.Now()
+	err := SendDiscordNotification(context.Background(), config, results)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("タイムアウトのエラーが返されませんでした")
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Errorf("タイムアウト以外のエラーが返されました: %v", err)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("タイムアウトまでに時間がかかりすぎています: %v", elapsed)
+	}
+}
+
+// TestNotifierTimeout Discord以外の通知先でもtimeoutの設定に従ってタイムアウトするかのテスト
+func TestNotifierTimeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	originalTelegram, originalPagerDuty := telegramAPIBase, pagerDutyEventsURL
+	telegramAPIBase, pagerDutyEventsURL = server.URL, server.URL
+	defer func() { telegramAPIBase, pagerDutyEventsURL = originalTelegram, originalPagerDuty }()
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+	}
+
+	testCases := []struct {
+		name  string
+		setup func(config *Config)
+		send  func(context.Context, *Config, []CertInfo) error
+	}{
+		{"Slack", func(c *Config) {
+			c.Slack.Enabled, c.Slack.WebhookURL, c.Slack.Timeout = true, server.URL, 1
+		}, SendSlackNotification},
+		{"Teams", func(c *Config) {
+			c.Teams.Enabled, c.Teams.WebhookURL, c.Teams.Timeout = true, server.URL, 1
+		}, SendTeamsNotification},
+		{"Telegram", func(c *Config) {
+			c.Telegram.Enabled, c.Telegram.BotToken, c.Telegram.ChatID, c.Telegram.Timeout = true, "token", "chat", 1
+		}, SendTelegramNotification},
+		{"Webhook", func(c *Config) {
+			c.Webhook.Enabled, c.Webhook.URL, c.Webhook.Timeout = true, server.URL, 1
+		}, SendWebhookNotification},
+		{"PagerDuty", func(c *Config) {
+			c.PagerDuty.Enabled, c.PagerDuty.RoutingKey, c.PagerDuty.Timeout = true, "key", 1
+		}, SendPagerDutyAlert},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{}
+			tc.setup(config)
+
+			start := time.Now()
+			err := tc.send(context.Background(), config, results)
+			elapsed := time.Since(start)
+
+			if err == nil {
+				t.Fatal("タイムアウトのエラーが返されませんでした")
+			}
+			if elapsed > 5*time.Second {
+				t.Errorf("タイムアウトまでに時間がかかりすぎています: %v", elapsed)
+			}
+		})
+	}
+}
+
+// TestNotifyHTTPClient 通知用HTTPクライアントのタイムアウト設定のテスト
+func TestNotifyHTTPClient(t *testing.T) {
+	if client := notifyHTTPClient(&Config{}, 0); client != notifyClient {
+		t.Error("タイムアウト未指定時に共通のクライアントが返されませんでした")
+	}
+	if client := notifyHTTPClient(&Config{}, 3); client.Timeout != 3*time.Second {
+		t.Errorf("タイムアウトが正しくありません。期待: 3s, 実際: %v", client.Timeout)
+	}
+	if notifyClient.Timeout != defaultTimeout {
+		t.Errorf("共通のクライアントのタイムアウトが変更されました: %v", notifyClient.Timeout)
+	}
+}
+
+// TestNotifyProxy proxy_urlを設定した場合に通知がプロキシ経由で送信されることのテスト
+func TestNotifyProxy(t *testing.T) {
+	// HTTPプロキシとして動作するモックサーバー（プロキシへのリクエストは絶対URLで送られる）
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := "proxy_url: " + proxy.URL + "\nslack:\n  enabled: true\n  webhook_url: http://hooks.slack.invalid/services/T000/B000/XXXX\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	results := []CertInfo{{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5}}
+	if err := SendSlackNotification(context.Background(), config, results); err != nil {
+		t.Fatalf("プロキシ経由の送信に失敗: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	expected := "POST http://hooks.slack.invalid/services/T000/B000/XXXX"
+	if len(proxied) != 1 || proxied[0] != expected {
+		t.Errorf("プロキシ経由で送信されていません。期待: [%s], 実際: %v", expected, proxied)
+	}
+
+	// 共通のクライアントは変更せず、環境変数のプロキシ設定に従うこと
+	if transport, ok := notifyClient.Transport.(*http.Transport); !ok || transport.Proxy == nil {
+		t.Error("共通のクライアントが環境変数のプロキシ設定を使用していません")
+	}
+}
+
+// TestParseProxyURL proxy_urlの解析のテスト
+func TestParseProxyURL(t *testing.T) {
+	for _, raw := range []string{"http://proxy.example.com:8080", "https://proxy.example.com", "socks5://127.0.0.1:1080"} {
+		if _, err := parseProxyURL(raw); err != nil {
+			t.Errorf("%s でエラーが発生しました: %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"proxy.example.com:8080", "ftp://proxy.example.com", "http://"} {
+		if _, err := parseProxyURL(raw); err == nil {
+			t.Errorf("%s でエラーが発生しませんでした", raw)
+		}
+	}
+}
+
+// TestDispatchNotificationsDryRun ドライランでは通知先が有効でも送信しないことのテスト
+func TestDispatchNotificationsDryRun(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	config := &Config{DryRun: true}
+	config.Discord.Enabled = true
+	config.Discord.WebhookURL = server.URL
+	config.Slack.Enabled = true
+	config.Slack.WebhookURL = server.URL
+	config.Webhook.Enabled = true
+	config.Webhook.URL = server.URL
+	config.StateFile = filepath.Join(dir, "state.json")
+	config.Alert.CooldownHours = 24
+	config.Alert.CooldownFile = filepath.Join(dir, "cooldown.json")
+
+	// ロガーのセットアップ（送信予定のログを確認する）
+	var logs strings.Builder
+	Logger = log.New(&logs, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+	}
+	DispatchNotifications(context.Background(), config, results)
+
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("ドライランで通知が送信されました: %d回", n)
+	}
+	for _, channel := range []string{"Discord", "Slack", "Webhook"} {
+		if !strings.Contains(logs.String(), "[dry-run] "+channel) {
+			t.Errorf("%sの送信予定がログに記録されていません: %s", channel, logs.String())
+		}
+	}
+
+	// 状態ファイルとクールダウンの記録は更新しない
+	for _, path := range []string{config.StateFile, config.Alert.CooldownFile} {
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("ドライランでファイルが書き込まれました: %s", path)
+		}
+	}
+}
+
+// TestDispatchNotificationsSendFailure 送信に失敗したサイトを通知済みとして記録せず、次回の実行で再通知するテスト
+func TestDispatchNotificationsSendFailure(t *testing.T) {
+	var discordRequests, webhookRequests int32
+	var webhookFailing atomic.Bool
+	webhookFailing.Store(true)
+	discord := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&discordRequests, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer discord.Close()
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&webhookRequests, 1)
+		if webhookFailing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer webhook.Close()
+
+	dir := t.TempDir()
+	config := &Config{}
+	config.Discord.Enabled = true
+	config.Discord.WebhookURL = discord.URL
+	config.Webhook.Enabled = true
+	config.Webhook.URL = webhook.URL
+	config.StateFile = filepath.Join(dir, "state.json")
+	config.Alert.CooldownHours = 24
+	config.Alert.CooldownFile = filepath.Join(dir, "cooldown.json")
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	delivered := CertInfo{SiteName: "Delivered Site", URL: "delivered.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3,
+		NotifyChannels: []string{"discord"}}
+	failed := CertInfo{SiteName: "Failed Site", URL: "failed.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3,
+		NotifyChannels: []string{"webhook"}}
+	results := []CertInfo{delivered, failed}
+	DispatchNotifications(context.Background(), config, results)
+
+	state, err := loadState(config.StateFile)
+	if err != nil {
+		t.Fatalf("状態ファイルの読み込みに失敗: %v", err)
+	}
+	if _, ok := state[stateKey(delivered)]; !ok {
+		t.Error("送信したサイトの状態が記録されていません")
+	}
+	if _, ok := state[stateKey(failed)]; ok {
+		t.Error("送信に失敗したサイトの状態が記録されています")
+	}
+	tracker, err := loadCooldown(config.Alert.CooldownFile, 24*time.Hour)
+	if err != nil {
+		t.Fatalf("クールダウンファイルの読み込みに失敗: %v", err)
+	}
+	if _, ok := tracker.last[cooldownKey(stateKey(delivered), "CRITICAL")]; !ok {
+		t.Error("送信したサイトの通知日時が記録されていません")
+	}
+	if _, ok := tracker.last[cooldownKey(stateKey(failed), "CRITICAL")]; ok {
+		t.Error("送信に失敗したサイトの通知日時が記録されています")
+	}
+
+	// 次回の実行では、送信に失敗したサイトだけを再通知する
+	webhookFailing.Store(false)
+	DispatchNotifications(context.Background(), config, results)
+	if n := atomic.LoadInt32(&discordRequests); n != 1 {
+		t.Errorf("Discordへの送信回数が正しくありません。期待: 1, 実際: %d", n)
+	}
+	if n := atomic.LoadInt32(&webhookRequests); n != 2 {
+		t.Errorf("Webhookへの送信回数が正しくありません。期待: 2, 実際: %d", n)
+	}
+	state, err = loadState(config.StateFile)
+	if err != nil {
+		t.Fatalf("状態ファイルの読み込みに失敗: %v", err)
+	}
+	if _, ok := state[stateKey(failed)]; !ok {
+		t.Error("再通知したサイトの状態が記録されていません")
+	}
+}
+
+// TestSendNotificationsRouting サイトのnotify_channelsに従って、各通知先に自分宛ての結果だけが送信されるテスト
+func TestSendNotificationsRouting(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	var events []pagerDutyEvent
+	newPagerDutyServer(t, &events, http.StatusAccepted)
+
+	var slackBodies []string
+	slackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		slackBodies = append(slackBodies, string(body))
+		w.Write([]byte("ok"))
+	}))
+	defer slackServer.Close()
+
+	config := &Config{}
+	config.Slack.Enabled = true
+	config.Slack.WebhookURL = slackServer.URL
+	config.PagerDuty.Enabled = true
+	config.PagerDuty.RoutingKey = "test-routing-key"
+
+	SendNotifications(context.Background(), config, []CertInfo{
+		{SiteName: "Production", URL: "www.example.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3, NotifyChannels: []string{"pagerduty"}},
+		{SiteName: "Development", URL: "dev.example.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3, NotifyChannels: []string{"slack"}},
+	})
+
+	if len(events) != 1 {
+		t.Fatalf("PagerDutyへの送信回数 期待: 1, 実際: %d", len(events))
+	}
+	if events[0].DedupKey != "cert-checker:www.example.com:443" {
+		t.Errorf("PagerDutyに送信されたサイトが正しくありません: %s", events[0].DedupKey)
+	}
+	if len(slackBodies) != 1 {
+		t.Fatalf("Slackへの送信回数 期待: 1, 実際: %d", len(slackBodies))
+	}
+	if !strings.Contains(slackBodies[0], "Development") || strings.Contains(slackBodies[0], "Production") {
+		t.Errorf("Slackに送信されたサイトが正しくありません: %s", slackBodies[0])
+	}
+}
+
+// TestRouteResults notify_channelsの省略・空の指定による通知先の振り分けのテスト
+func TestRouteResults(t *testing.T) {
+	var config struct {
+		Sites []Site `yaml:"sites"`
+	}
+	data := `
+sites:
+  - url: all.example.com
+  - url: slack.example.com
+    notify_channels: [slack]
+  - url: none.example.com
+    notify_channels: []
+`
+	if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("設定の解析に失敗: %v", err)
+	}
+	results := make([]CertInfo, len(config.Sites))
+	for i, site := range config.Sites {
+		results[i] = CertInfo{URL: site.URL, NotifyChannels: site.NotifyChannels}
+	}
+
+	testCases := []struct {
+		channel  string
+		expected []string
+	}{
+		{channel: "slack", expected: []string{"all.example.com", "slack.example.com"}},
+		{channel: "email", expected: []string{"all.example.com"}},
+	}
+	for _, tc := range testCases {
+		var urls []string
+		for _, result := range routeResults(results, tc.channel) {
+			urls = append(urls, result.URL)
+		}
+		if strings.Join(urls, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("%s 期待: %v, 実際: %v", tc.channel, tc.expected, urls)
+		}
+	}
+}
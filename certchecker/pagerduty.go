@@ -0,0 +1,140 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// pagerDutyEventsURL PagerDuty Events API v2のエンドポイント
+var pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+// defaultPagerDutySeverity ステータスに対応するPagerDutyのseverityのデフォルト値
+var defaultPagerDutySeverity = map[string]string{
+	"CRITICAL": "critical",
+	"ERROR":    "error",
+}
+
+// pagerDutyPayload イベントの内容
+type pagerDutyPayload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+// pagerDutyEvent Events API v2に送信するイベント
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// pagerDutyDedupKey サイトごとに一意な重複排除キー
+// 同じキーのイベントは同じインシデントとして扱われるため、実行のたびにインシデントが増えることはない
+func pagerDutyDedupKey(cert CertInfo) string {
+	return "cert-checker:" + displayAddress(cert.URL, cert.Port)
+}
+
+// pagerDutyIncidentOpen 前回の実行でインシデントを作成したサイトか（前回CRITICAL/ERRORだったサイト）
+// WARNINGから復旧したサイトはインシデントを作成していないため対象外とする
+func pagerDutyIncidentOpen(cert CertInfo) bool {
+	return cert.PreviousStatus == "CRITICAL" || cert.PreviousStatus == "ERROR"
+}
+
+// SendPagerDutyAlert CRITICAL/ERRORのサイトのインシデントを作成し、CRITICAL/ERRORから回復したサイトのインシデントを解決する
+// 一部のイベントの送信に失敗しても残りのサイトの送信を続け、失敗したものをまとめて返す
+func SendPagerDutyAlert(ctx context.Context, config *Config, results []CertInfo) error {
+	if !config.PagerDuty.Enabled {
+		LogDebugf("PagerDuty連携は無効です")
+		return nil
+	}
+
+	if config.PagerDuty.RoutingKey == "" {
+		LogWarnf("PagerDutyのルーティングキーが設定されていません")
+		return nil
+	}
+
+	client := notifyHTTPClient(config, config.PagerDuty.Timeout)
+	triggered, resolved := 0, 0
+	var errs []error
+	for _, cert := range results {
+		event := pagerDutyEvent{
+			RoutingKey: config.PagerDuty.RoutingKey,
+			DedupKey:   pagerDutyDedupKey(cert),
+		}
+
+		if cert.Status == "CRITICAL" || cert.Status == "ERROR" {
+			severity := config.PagerDuty.Severity[cert.Status]
+			if severity == "" {
+				severity = defaultPagerDutySeverity[cert.Status]
+			}
+			// CRITICALが続いている場合は、エスカレーションレベルの分だけseverityを引き上げる
+			severity = escalateSeverity(severity, cert.EscalationLevel)
+
+			details := map[string]string{
+				"status": cert.Status,
+			}
+			if cert.Status != "ERROR" {
+				details["days_remaining"] = fmt.Sprintf("%d", cert.DaysRemaining)
+				details["not_after"] = config.formatTime(cert.NotAfter)
+				details["issuer"] = cert.Issuer
+			}
+			if cert.ErrorMessage != "" {
+				details["message"] = cert.ErrorMessage
+			}
+			if cert.EscalationLevel > 0 {
+				details["escalation_level"] = fmt.Sprintf("%d", cert.EscalationLevel)
+				details["critical_runs"] = fmt.Sprintf("%d", cert.CriticalRuns)
+			}
+
+			event.EventAction = "trigger"
+			event.Payload = &pagerDutyPayload{
+				Summary:       fmt.Sprintf(config.message("pagerduty_summary"), cert.Status, cert.SiteName, displayAddress(cert.URL, cert.Port)),
+				Source:        displayAddress(cert.URL, cert.Port),
+				Severity:      severity,
+				CustomDetails: details,
+			}
+		} else if pagerDutyIncidentOpen(cert) {
+			event.EventAction = "resolve"
+		} else {
+			// インシデントを作成していないサイトには解決イベントを送らない
+			continue
+		}
+
+		if err := postPagerDutyEvent(ctx, client, event); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if event.EventAction == "trigger" {
+			triggered++
+		} else {
+			resolved++
+		}
+	}
+
+	LogInfof("PagerDutyにイベントを送信しました（発生: %d件、解決: %d件）", triggered, resolved)
+	return errors.Join(errs...)
+}
+
+// postPagerDutyEvent イベントを1件送信する
+func postPagerDutyEvent(ctx context.Context, client *http.Client, event pagerDutyEvent) error {
+	jsonData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("JSONのマーシャルに失敗: %v", err)
+	}
+
+	resp, err := postJSON(ctx, client, pagerDutyEventsURL, jsonData)
+	if err != nil {
+		return fmt.Errorf("PagerDutyへのイベント送信に失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("PagerDutyがエラーを返しました: %d (%s)", resp.StatusCode, event.DedupKey)
+	}
+	return nil
+}
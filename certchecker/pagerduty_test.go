@@ -0,0 +1,157 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newPagerDutyServer 受信したイベントを記録するモックのEvents APIサーバーを起動する
+func newPagerDutyServer(t *testing.T, events *[]pagerDutyEvent, status int) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerDutyEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("イベントの解析に失敗: %v", err)
+		}
+		*events = append(*events, event)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	original := pagerDutyEventsURL
+	pagerDutyEventsURL = server.URL
+	t.Cleanup(func() { pagerDutyEventsURL = original })
+}
+
+// TestSendPagerDutyAlert PagerDutyへのイベント送信のテスト
+func TestSendPagerDutyAlert(t *testing.T) {
+	var events []pagerDutyEvent
+	newPagerDutyServer(t, &events, http.StatusAccepted)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.PagerDuty.Enabled = true
+	config.PagerDuty.RoutingKey = "test-routing-key"
+	config.PagerDuty.Severity = map[string]string{"ERROR": "warning"}
+
+	results := []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+		{SiteName: "Error Site", URL: "error.com", Port: 443, Status: "ERROR", ErrorMessage: "Connection failed"},
+		{SiteName: "Recovered Site", URL: "recovered.com", Port: 443, Status: "OK", DaysRemaining: 90, Recovered: true, PreviousStatus: "CRITICAL"},
+		{SiteName: "Improved Site", URL: "improved.com", Port: 443, Status: "WARNING", DaysRemaining: 20, PreviousStatus: "CRITICAL"},
+		// インシデントを作成していないサイトには解決イベントを送らない
+		{SiteName: "OK Site", URL: "ok.com", Port: 443, Status: "OK", DaysRemaining: 90},
+		{SiteName: "Warning Site", URL: "warning.com", Port: 443, Status: "WARNING", DaysRemaining: 20, PreviousStatus: "OK"},
+	}
+
+	if err := SendPagerDutyAlert(context.Background(), config, results); err != nil {
+		t.Fatalf("PagerDutyへの送信でエラーが発生しました: %v", err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("イベント数が正しくありません。期待: 4, 実際: %d", len(events))
+	}
+
+	testCases := []struct {
+		action   string
+		dedupKey string
+		severity string
+	}{
+		{action: "trigger", dedupKey: "cert-checker:critical.com:443", severity: "critical"},
+		{action: "trigger", dedupKey: "cert-checker:error.com:443", severity: "warning"},
+		{action: "resolve", dedupKey: "cert-checker:recovered.com:443"},
+		{action: "resolve", dedupKey: "cert-checker:improved.com:443"},
+	}
+	for i, tc := range testCases {
+		event := events[i]
+		if event.RoutingKey != "test-routing-key" {
+			t.Errorf("%d件目のルーティングキーが正しくありません: %s", i+1, event.RoutingKey)
+		}
+		if event.EventAction != tc.action || event.DedupKey != tc.dedupKey {
+			t.Errorf("%d件目のイベントが正しくありません。期待: %s/%s, 実際: %s/%s", i+1, tc.action, tc.dedupKey, event.EventAction, event.DedupKey)
+		}
+		if tc.severity != "" && (event.Payload == nil || event.Payload.Severity != tc.severity) {
+			t.Errorf("%d件目のseverityが正しくありません。期待: %s, 実際: %+v", i+1, tc.severity, event.Payload)
+		}
+		if tc.action == "resolve" && event.Payload != nil {
+			t.Errorf("解決イベントにpayloadが含まれています: %+v", event.Payload)
+		}
+	}
+
+	// 再実行しても同じ重複排除キーが使われる
+	events = nil
+	if err := SendPagerDutyAlert(context.Background(), config, results[:1]); err != nil {
+		t.Fatalf("PagerDutyへの送信でエラーが発生しました: %v", err)
+	}
+	if len(events) != 1 || events[0].DedupKey != "cert-checker:critical.com:443" {
+		t.Errorf("再実行時の重複排除キーが正しくありません: %+v", events)
+	}
+}
+
+// TestSendPagerDutyAlertWarningRecovered WARNINGから復旧したサイトには解決イベントを送らないテスト
+func TestSendPagerDutyAlertWarningRecovered(t *testing.T) {
+	var events []pagerDutyEvent
+	newPagerDutyServer(t, &events, http.StatusAccepted)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.PagerDuty.Enabled = true
+	config.PagerDuty.RoutingKey = "test-routing-key"
+
+	results := []CertInfo{
+		{SiteName: "Renewed Site", URL: "renewed.com", Port: 443, Status: "OK", DaysRemaining: 90, Recovered: true, PreviousStatus: "WARNING"},
+	}
+	if err := SendPagerDutyAlert(context.Background(), config, results); err != nil {
+		t.Fatalf("PagerDutyへの送信でエラーが発生しました: %v", err)
+	}
+	if len(events) != 0 {
+		t.Errorf("インシデントを作成していないサイトにイベントが送信されました: %+v", events)
+	}
+}
+
+// TestSendPagerDutyAlertError Events APIがエラーを返した場合のテスト
+func TestSendPagerDutyAlertError(t *testing.T) {
+	var events []pagerDutyEvent
+	newPagerDutyServer(t, &events, http.StatusBadRequest)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.PagerDuty.Enabled = true
+	config.PagerDuty.RoutingKey = "test-routing-key"
+
+	// 1件目の送信に失敗しても残りのサイトの送信を続け、すべてのエラーを返す
+	results := []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL"},
+		{SiteName: "Error Site", URL: "error.com", Port: 443, Status: "ERROR"},
+	}
+	err := SendPagerDutyAlert(context.Background(), config, results)
+	if err == nil {
+		t.Fatal("Events APIのエラーが返されませんでした")
+	}
+	if len(events) != 2 {
+		t.Errorf("イベント数が正しくありません。期待: 2, 実際: %d", len(events))
+	}
+	for _, key := range []string{"cert-checker:critical.com:443", "cert-checker:error.com:443"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("エラーに %s が含まれていません: %v", key, err)
+		}
+	}
+
+	// 無効時は送信しない
+	events = nil
+	config.PagerDuty.Enabled = false
+	if err := SendPagerDutyAlert(context.Background(), config, results); err != nil || len(events) != 0 {
+		t.Errorf("無効時に送信されました: err=%v, events=%d", err, len(events))
+	}
+}
@@ -0,0 +1,42 @@
+package certchecker
+
+import (
+	"fmt"
+	"time"
+)
+
+// notifyNow 通知の判定に使用する現在時刻（テストで置き換える）
+var notifyNow = time.Now
+
+// parseClock HH:MM 形式の時刻を解析し、0時からの経過時間を返す
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("HH:MM 形式で指定してください（現在: %s）", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// inQuietHours 指定した時刻がalert.quiet_hoursの時間帯（report.timezoneの時刻）に含まれるか
+// 開始が終了より遅い場合（22:00〜07:00など）は日付をまたぐ時間帯として扱う
+func inQuietHours(config *Config, now time.Time) bool {
+	quiet := config.Alert.QuietHours
+	if quiet.Start == "" || quiet.End == "" {
+		return false
+	}
+	start, err := parseClock(quiet.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(quiet.End)
+	if err != nil {
+		return false
+	}
+
+	local := now.In(config.reportLocation())
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if start <= end {
+		return clock >= start && clock < end
+	}
+	return clock >= start || clock < end
+}
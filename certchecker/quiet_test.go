@@ -0,0 +1,151 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestInQuietHours 静穏時間帯の判定のテスト
+func TestInQuietHours(t *testing.T) {
+	testCases := []struct {
+		name     string
+		start    string
+		end      string
+		clock    string
+		expected bool
+	}{
+		{name: "指定なし", clock: "03:00", expected: false},
+		{name: "日付をまたぐ時間帯の深夜", start: "22:00", end: "07:00", clock: "03:00", expected: true},
+		{name: "日付をまたぐ時間帯の開始時刻", start: "22:00", end: "07:00", clock: "22:00", expected: true},
+		{name: "日付をまたぐ時間帯の終了時刻", start: "22:00", end: "07:00", clock: "07:00", expected: false},
+		{name: "日付をまたぐ時間帯の日中", start: "22:00", end: "07:00", clock: "12:00", expected: false},
+		{name: "日中の時間帯の内側", start: "12:00", end: "13:00", clock: "12:30", expected: true},
+		{name: "日中の時間帯の外側", start: "12:00", end: "13:00", clock: "13:30", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{}
+			config.location = JST
+			config.Alert.QuietHours.Start = tc.start
+			config.Alert.QuietHours.End = tc.end
+
+			clock, _ := time.Parse("15:04", tc.clock)
+			now := time.Date(2026, 10, 16, clock.Hour(), clock.Minute(), 0, 0, JST)
+			if actual := inQuietHours(config, now); actual != tc.expected {
+				t.Errorf("期待: %v, 実際: %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+// TestNotifyTrackerQuietHours 静穏時間帯の判定がshouldNotifyで行われ、CRITICAL・ERROR以外の結果が見送られるテスト
+func TestNotifyTrackerQuietHours(t *testing.T) {
+	original := notifyNow
+	t.Cleanup(func() { notifyNow = original })
+
+	config := &Config{}
+	config.location = JST
+	config.Alert.QuietHours.Start = "22:00"
+	config.Alert.QuietHours.End = "07:00"
+
+	testCases := []struct {
+		name     string
+		hour     int
+		status   string
+		notify   bool
+		deferred bool
+	}{
+		{name: "時間帯の内側のWARNING", hour: 3, status: "WARNING", notify: false, deferred: true},
+		{name: "時間帯の内側のOK", hour: 3, status: "OK", notify: false, deferred: true},
+		{name: "時間帯の内側のCRITICAL", hour: 3, status: "CRITICAL", notify: true},
+		{name: "時間帯の内側のERROR", hour: 3, status: "ERROR", notify: true},
+		{name: "時間帯の外側のWARNING", hour: 9, status: "WARNING", notify: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifyNow = func() time.Time { return time.Date(2026, 10, 16, tc.hour, 0, 0, 0, JST) }
+			tracker, err := loadNotifyTracker(config)
+			if err != nil {
+				t.Fatalf("トラッカーの作成に失敗: %v", err)
+			}
+			notify, deferred := tracker.shouldNotify(CertInfo{SiteName: "site", Status: tc.status})
+			if notify != tc.notify || deferred != tc.deferred {
+				t.Errorf("判定が正しくありません。期待: notify=%v deferred=%v, 実際: notify=%v deferred=%v", tc.notify, tc.deferred, notify, deferred)
+			}
+		})
+	}
+}
+
+// TestDispatchNotificationsQuietHours 静穏時間帯はCRITICAL・ERRORだけを通知し、時間帯が終わった後に見送った通知を送るテスト
+func TestDispatchNotificationsQuietHours(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	original := notifyNow
+	t.Cleanup(func() { notifyNow = original })
+
+	config := &Config{}
+	config.location = JST
+	config.Alert.QuietHours.Start = "22:00"
+	config.Alert.QuietHours.End = "07:00"
+	config.Discord.Enabled = true
+	config.Discord.WebhookURL = server.URL
+	config.StateFile = filepath.Join(t.TempDir(), "state.json")
+
+	newResults := func() []CertInfo {
+		return []CertInfo{
+			{SiteName: "Warning Site", URL: "warning.example.com", Port: 443, Status: "WARNING", DaysRemaining: 20},
+			{SiteName: "Critical Site", URL: "critical.example.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+		}
+	}
+
+	// 時間帯の内側（午前3時）はCRITICALだけを通知する
+	notifyNow = func() time.Time { return time.Date(2026, 10, 16, 3, 0, 0, 0, JST) }
+	DispatchNotifications(context.Background(), config, newResults())
+	mu.Lock()
+	if len(bodies) != 1 {
+		t.Fatalf("送信回数が正しくありません。期待: 1, 実際: %d", len(bodies))
+	}
+	if strings.Contains(bodies[0], "Warning Site") {
+		t.Error("静穏時間帯にWARNINGが通知されました")
+	}
+	if !strings.Contains(bodies[0], "Critical Site") {
+		t.Error("静穏時間帯にCRITICALが通知されていません")
+	}
+	mu.Unlock()
+
+	// 時間帯の外側（午前9時）は、見送ったWARNINGを通知する（CRITICALは通知済みのため対象外）
+	notifyNow = func() time.Time { return time.Date(2026, 10, 16, 9, 0, 0, 0, JST) }
+	DispatchNotifications(context.Background(), config, newResults())
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("送信回数が正しくありません。期待: 2, 実際: %d", len(bodies))
+	}
+	if !strings.Contains(bodies[1], "Warning Site") {
+		t.Error("静穏時間帯に見送ったWARNINGが時間帯の終了後に通知されていません")
+	}
+	if strings.Contains(bodies[1], "Critical Site") {
+		t.Error("通知済みのCRITICALが再度通知されました")
+	}
+}
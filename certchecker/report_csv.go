@@ -0,0 +1,41 @@
+package certchecker
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+)
+
+// csvHeader CSVレポートのヘッダー行
+var csvHeader = []string{"site", "url", "port", "issuer", "subject", "not_after", "days_remaining", "status", "error"}
+
+// GenerateCSVReport CSVレポートを生成
+// 証明書を取得できなかったサイトも同じ列数で出力し、証明書に関する列は空にする
+// 有効期限はreport.timezoneのタイムゾーンのRFC3339形式で出力する
+func GenerateCSVReport(config *Config, results []CertInfo) string {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	w.Write(csvHeader)
+	for _, cert := range results {
+		record := []string{
+			cert.SiteName,
+			cert.URL,
+			strconv.Itoa(cert.Port),
+			"", "", "", "",
+			cert.Status,
+			cert.ErrorMessage,
+		}
+		if cert.Status != "ERROR" {
+			record[3] = cert.Issuer
+			record[4] = cert.Subject
+			record[5] = cert.NotAfter.In(config.reportLocation()).Format(time.RFC3339)
+			record[6] = strconv.Itoa(cert.DaysRemaining)
+		}
+		w.Write(record)
+	}
+	w.Flush()
+
+	return buf.String()
+}
@@ -0,0 +1,89 @@
+package certchecker
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateCSVReport CSVレポート生成のテスト
+func TestGenerateCSVReport(t *testing.T) {
+	results := []CertInfo{
+		{
+			SiteName:      "Test Site",
+			URL:           "example.com",
+			Port:          443,
+			Issuer:        "Example Inc, Ltd",
+			Subject:       "example.com",
+			NotAfter:      time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC),
+			DaysRemaining: 45,
+			Status:        "OK",
+		},
+		{
+			SiteName:     "Error Site",
+			URL:          "error.example.com",
+			Port:         8443,
+			Status:       "ERROR",
+			ErrorMessage: "証明書の取得に失敗: dial tcp: connection refused, retry later",
+		},
+	}
+
+	report := GenerateCSVReport(&Config{}, results)
+
+	records, err := csv.NewReader(strings.NewReader(report)).ReadAll()
+	if err != nil {
+		t.Fatalf("CSVの解析に失敗: %v\n%s", err, report)
+	}
+	if len(records) != 3 {
+		t.Fatalf("行数が正しくありません。期待: 3, 実際: %d", len(records))
+	}
+	if strings.Join(records[0], ",") != "site,url,port,issuer,subject,not_after,days_remaining,status,error" {
+		t.Errorf("ヘッダー行が正しくありません: %v", records[0])
+	}
+
+	// カンマを含む発行者も1つの列として読み込める
+	ok := records[1]
+	if ok[3] != "Example Inc, Ltd" {
+		t.Errorf("発行者が正しくありません。期待: Example Inc, Ltd, 実際: %s", ok[3])
+	}
+	if ok[5] != "2026-03-01T12:00:00+09:00" {
+		t.Errorf("有効期限が正しくありません。期待: 2026-03-01T12:00:00+09:00, 実際: %s", ok[5])
+	}
+	if ok[6] != "45" || ok[7] != "OK" {
+		t.Errorf("残り日数またはステータスが正しくありません: %v", ok)
+	}
+
+	// エラー行も同じ列数で、証明書に関する列は空
+	errRow := records[2]
+	if len(errRow) != len(records[0]) {
+		t.Errorf("エラー行の列数が正しくありません。期待: %d, 実際: %d", len(records[0]), len(errRow))
+	}
+	if errRow[2] != "8443" || errRow[5] != "" || errRow[6] != "" || errRow[7] != "ERROR" {
+		t.Errorf("エラー行が正しくありません: %v", errRow)
+	}
+	if errRow[8] != results[1].ErrorMessage {
+		t.Errorf("エラーメッセージが正しくありません: %s", errRow[8])
+	}
+}
+
+// TestGenerateCSVReportTimezone report.timezoneがCSVレポートの有効期限に反映されることのテスト
+func TestGenerateCSVReportTimezone(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("タイムゾーンの情報がありません: %v", err)
+	}
+	config := &Config{}
+	config.location = location
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "example.com", Port: 443, NotAfter: time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC), DaysRemaining: 45, Status: "OK"},
+	}
+
+	records, err := csv.NewReader(strings.NewReader(GenerateCSVReport(config, results))).ReadAll()
+	if err != nil {
+		t.Fatalf("CSVの解析に失敗: %v", err)
+	}
+	if expected := "2026-02-28T22:00:00-05:00"; records[1][5] != expected {
+		t.Errorf("有効期限が正しくありません。期待: %s, 実際: %s", expected, records[1][5])
+	}
+}
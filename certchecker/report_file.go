@@ -0,0 +1,37 @@
+package certchecker
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// writeReportFiles report.text_fileとreport.html_fileで指定したファイルにレポートを書き出す
+// 書き出しに失敗したファイルがあっても、残りのファイルの書き出しは続ける
+func writeReportFiles(config *Config, results []CertInfo) {
+	files := []struct {
+		name     string
+		path     string
+		generate func(*Config, []CertInfo) string
+	}{
+		{logMessagef("テキスト"), config.Report.TextFile, GenerateTextReport},
+		{"HTML", config.Report.HTMLFile, GenerateHTMLReport},
+	}
+	for _, file := range files {
+		if file.path == "" {
+			continue
+		}
+		if err := writeReportFile(file.path, file.generate(config, results)); err != nil {
+			LogErrorf("%sレポートの書き出しに失敗しました: %v", file.name, err)
+		} else {
+			LogInfof("%sレポートを書き出しました: %s", file.name, file.path)
+		}
+	}
+}
+
+// writeReportFile 親ディレクトリを作成してからレポートをファイルに書き出す
+func writeReportFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, content)
+}
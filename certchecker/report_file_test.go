@@ -0,0 +1,58 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWriteReportFiles 存在しないディレクトリにもレポートファイルを書き出せることのテスト
+func TestWriteReportFiles(t *testing.T) {
+	dir := t.TempDir()
+	config := &Config{}
+	config.Report.HTMLFile = filepath.Join(dir, "archive", "2026", "report.html")
+	config.Report.TextFile = filepath.Join(dir, "archive", "report.txt")
+
+	results := []CertInfo{
+		{SiteName: "Example Site", URL: "example.com", Port: 443, Status: "WARNING", DaysRemaining: 20, Issuer: "Let's Encrypt"},
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	writeReportFiles(config, results)
+
+	data, err := os.ReadFile(config.Report.HTMLFile)
+	if err != nil {
+		t.Fatalf("HTMLレポートが書き出されていません: %v", err)
+	}
+	html := string(data)
+	for _, expected := range []string{"<html", "Example Site", "WARNING", "20"} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("HTMLレポートに %q が含まれていません", expected)
+		}
+	}
+
+	data, err = os.ReadFile(config.Report.TextFile)
+	if err != nil {
+		t.Fatalf("テキストレポートが書き出されていません: %v", err)
+	}
+	if !strings.Contains(string(data), "サイト名: Example Site") {
+		t.Errorf("テキストレポートの内容が正しくありません: %s", data)
+	}
+	if strings.Contains(string(data), "\x1b[") {
+		t.Error("テキストレポートのファイルにエスケープシーケンスが含まれています")
+	}
+
+	// 一時ファイルが残っていないこと
+	entries, err := os.ReadDir(filepath.Dir(config.Report.HTMLFile))
+	if err != nil {
+		t.Fatalf("ディレクトリの読み込みに失敗: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("ファイル数が正しくありません。期待: 1, 実際: %d", len(entries))
+	}
+}
@@ -0,0 +1,61 @@
+package certchecker
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// jsonReport JSONレポートの構造
+type jsonReport struct {
+	CheckTime string      `json:"check_time"`
+	Summary   jsonSummary `json:"summary"`
+	Results   []CertInfo  `json:"results"`
+}
+
+// jsonSummary ステータスごとのサイト数
+type jsonSummary struct {
+	Total    int `json:"total"`
+	OK       int `json:"ok"`
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+	Error    int `json:"error"`
+}
+
+// summarizeResults ステータスごとのサイト数を集計
+func summarizeResults(results []CertInfo) jsonSummary {
+	summary := jsonSummary{Total: len(results)}
+	for _, result := range results {
+		switch result.Status {
+		case "OK":
+			summary.OK++
+		case "WARNING":
+			summary.Warning++
+		case "CRITICAL":
+			summary.Critical++
+		case "ERROR":
+			summary.Error++
+		}
+	}
+	return summary
+}
+
+// GenerateJSONReport JSONレポートを生成
+// チェック日時はreport.timezoneのタイムゾーンのRFC3339形式で出力する
+func GenerateJSONReport(config *Config, results []CertInfo) string {
+	report := jsonReport{
+		CheckTime: time.Now().In(config.reportLocation()).Format(time.RFC3339),
+		Summary:   summarizeResults(results),
+		Results:   results,
+	}
+	if report.Results == nil {
+		report.Results = []CertInfo{}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		// CertInfoは常にJSONに変換できるため、ここには到達しない
+		LogErrorf("JSONレポートの生成に失敗: %v", err)
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,133 @@
+package certchecker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestGenerateJSONReport JSONレポート生成のテスト
+func TestGenerateJSONReport(t *testing.T) {
+	notAfter := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []CertInfo{
+		{
+			SiteName:          "Test Site",
+			URL:               "example.com",
+			Port:              443,
+			Issuer:            "Test CA",
+			Subject:           "example.com",
+			NotBefore:         notAfter.AddDate(0, -3, 0),
+			NotAfter:          notAfter,
+			DaysRemaining:     45,
+			Status:            "OK",
+			SANs:              []string{"example.com", "www.example.com"},
+			FingerprintSHA256: "e7305535c50eccd93ac952b4d7680a85c0938b33e9519af8a07c14ffd2c90a95",
+		},
+		{
+			SiteName:      "Warning Site",
+			URL:           "warning.example.com",
+			Port:          443,
+			DaysRemaining: 20,
+			Status:        "WARNING",
+		},
+		{
+			SiteName:     "Error Site",
+			URL:          "error.example.com",
+			Port:         8443,
+			Status:       "ERROR",
+			ErrorMessage: "証明書の取得に失敗",
+		},
+	}
+
+	report := GenerateJSONReport(&Config{}, results)
+
+	var parsed struct {
+		CheckTime string `json:"check_time"`
+		Summary   struct {
+			Total    int `json:"total"`
+			OK       int `json:"ok"`
+			Warning  int `json:"warning"`
+			Critical int `json:"critical"`
+			Error    int `json:"error"`
+		} `json:"summary"`
+		Results []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(report), &parsed); err != nil {
+		t.Fatalf("JSONレポートの解析に失敗: %v\n%s", err, report)
+	}
+
+	if _, err := time.Parse(time.RFC3339, parsed.CheckTime); err != nil {
+		t.Errorf("チェック日時がRFC3339形式ではありません: %s", parsed.CheckTime)
+	}
+	if parsed.Summary.Total != 3 || parsed.Summary.OK != 1 || parsed.Summary.Warning != 1 || parsed.Summary.Critical != 0 || parsed.Summary.Error != 1 {
+		t.Errorf("集計が正しくありません: %+v", parsed.Summary)
+	}
+	if len(parsed.Results) != 3 {
+		t.Fatalf("結果の件数が正しくありません。期待: 3, 実際: %d", len(parsed.Results))
+	}
+
+	first := parsed.Results[0]
+	if first["site_name"] != "Test Site" {
+		t.Errorf("サイト名が正しくありません: %v", first["site_name"])
+	}
+	if first["port"] != float64(443) {
+		t.Errorf("ポートが正しくありません: %v", first["port"])
+	}
+	if first["not_after"] != "2026-03-01T12:00:00Z" {
+		t.Errorf("有効期限がRFC3339形式ではありません: %v", first["not_after"])
+	}
+	if first["days_remaining"] != float64(45) {
+		t.Errorf("残り日数が正しくありません: %v", first["days_remaining"])
+	}
+	if first["fingerprint_sha256"] != results[0].FingerprintSHA256 {
+		t.Errorf("フィンガープリントが正しくありません: %v", first["fingerprint_sha256"])
+	}
+	if sans, ok := first["sans"].([]interface{}); !ok || len(sans) != 2 {
+		t.Errorf("SANが正しくありません: %v", first["sans"])
+	}
+
+	errorResult := parsed.Results[2]
+	if errorResult["status"] != "ERROR" || errorResult["error_message"] != "証明書の取得に失敗" {
+		t.Errorf("エラー結果が正しくありません: %v", errorResult)
+	}
+}
+
+// TestGenerateJSONReportEmpty 結果がない場合のJSONレポートのテスト
+func TestGenerateJSONReportEmpty(t *testing.T) {
+	var parsed struct {
+		Results []CertInfo `json:"results"`
+	}
+	report := GenerateJSONReport(&Config{}, nil)
+	if err := json.Unmarshal([]byte(report), &parsed); err != nil {
+		t.Fatalf("JSONレポートの解析に失敗: %v", err)
+	}
+	if parsed.Results == nil {
+		t.Error("結果が空配列ではなくnullになっています")
+	}
+}
+
+// TestGenerateJSONReportTimezone report.timezoneがJSONレポートのチェック日時に反映されることのテスト
+func TestGenerateJSONReportTimezone(t *testing.T) {
+	location, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("タイムゾーンの情報がありません: %v", err)
+	}
+	config := &Config{}
+	config.location = location
+
+	var parsed struct {
+		CheckTime string `json:"check_time"`
+	}
+	if err := json.Unmarshal([]byte(GenerateJSONReport(config, nil)), &parsed); err != nil {
+		t.Fatalf("JSONレポートの解析に失敗: %v", err)
+	}
+	checkTime, err := time.Parse(time.RFC3339, parsed.CheckTime)
+	if err != nil {
+		t.Fatalf("チェック日時がRFC3339形式ではありません: %s", parsed.CheckTime)
+	}
+	_, offset := checkTime.Zone()
+	_, expected := checkTime.In(location).Zone()
+	if offset != expected {
+		t.Errorf("チェック日時のタイムゾーンが正しくありません。期待: %d, 実際: %d (%s)", expected, offset, parsed.CheckTime)
+	}
+}
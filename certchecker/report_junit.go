@@ -0,0 +1,121 @@
+package certchecker
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// junitTestSuites JUnit XMLのルート要素
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite チェック全体を表すテストスイート
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+// junitTestCase サイトごとのテストケース
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+// junitFailure 失敗（failure）またはエラー（error）になったテストケースの詳細
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitSuiteName JUnit XMLのテストスイート名
+const junitSuiteName = "cert-checker"
+
+// GenerateJUnitReport JUnit XML形式のレポートを生成
+// サイトごとに1つのテストケースとし、CRITICALを失敗（failure）、チェックできなかったERRORをエラー（error）として扱う
+// WARNINGは成功とし、内容をsystem-outに出力する
+// timestamp属性はJUnitの形式に合わせてreport.timezoneの時刻をISO 8601で出力し、概要の日時はreport.date_formatに従う
+// 失敗のメッセージと概要の項目名はreport.languageの表記を使用する
+func GenerateJUnitReport(config *Config, results []CertInfo) string {
+	suite := junitTestSuite{
+		Name:      junitSuiteName,
+		Tests:     len(results),
+		Timestamp: time.Now().In(config.reportLocation()).Format("2006-01-02T15:04:05"),
+	}
+	for _, result := range results {
+		testCase := junitTestCase{
+			Name:      result.SiteName,
+			ClassName: junitSuiteName + "." + displayAddress(result.URL, result.Port),
+			SystemOut: junitSummary(config, result),
+		}
+		detail := &junitFailure{
+			Message: junitFailureMessage(config, result),
+			Type:    result.Status,
+			Text:    junitSummary(config, result),
+		}
+		// CIで証明書の問題と接続先やネットワークの問題を区別できるよう、ERRORはerror要素で報告する
+		switch result.Status {
+		case "CRITICAL":
+			testCase.Failure = detail
+			suite.Failures++
+		case "ERROR":
+			testCase.Error = detail
+			suite.Errors++
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	report := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Errors:   suite.Errors,
+		Suites:   []junitTestSuite{suite},
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		// 構造体は常にXMLに変換できるため、ここには到達しない
+		LogErrorf("JUnitレポートの生成に失敗: %v", err)
+		return ""
+	}
+	return xml.Header + string(data) + "\n"
+}
+
+// junitFailureMessage 失敗したテストケースのメッセージを作成
+func junitFailureMessage(config *Config, result CertInfo) string {
+	if result.ErrorMessage != "" {
+		return result.ErrorMessage
+	}
+	if result.Expired {
+		return fmt.Sprintf(config.message("junit_expired"), -result.DaysRemaining)
+	}
+	return fmt.Sprintf(config.message("junit_remaining"), result.DaysRemaining)
+}
+
+// junitSummary テストケースに出力する証明書の概要を作成
+func junitSummary(config *Config, result CertInfo) string {
+	if result.Status == "ERROR" {
+		return fmt.Sprintf("%s: %s\n%s: %s", config.message("status"), result.Status, config.message("error"), result.ErrorMessage)
+	}
+	summary := fmt.Sprintf("%s: %s\n%s: %s\n%s: %s\n%s: %s",
+		config.message("status"), result.Status,
+		config.message("issuer"), result.Issuer,
+		config.message("valid_until"), config.formatTime(result.NotAfter),
+		config.message("days_remaining"), fmt.Sprintf(config.message("days"), result.DaysRemaining))
+	if result.ErrorMessage != "" {
+		summary += "\n" + config.message("warning") + ": " + result.ErrorMessage
+	}
+	return summary
+}
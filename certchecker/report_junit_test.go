@@ -0,0 +1,144 @@
+package certchecker
+
+import (
+	"encoding/xml"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateJUnitReport JUnit XMLレポート生成のテスト
+func TestGenerateJUnitReport(t *testing.T) {
+	notAfter := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	results := []CertInfo{
+		{SiteName: "OK Site", URL: "ok.example.com", Port: 443, Issuer: "Let's Encrypt", NotAfter: notAfter, DaysRemaining: 60, Status: "OK"},
+		{SiteName: "Warning Site", URL: "warning.example.com", Port: 443, Issuer: "DigiCert", NotAfter: notAfter, DaysRemaining: 20, Status: "WARNING"},
+		{SiteName: "Critical Site", URL: "critical.example.com", Port: 443, Issuer: "GlobalSign", NotAfter: notAfter, DaysRemaining: 5, Status: "CRITICAL"},
+		{SiteName: "Error <Site>", URL: "error.example.com", Port: 8443, Status: "ERROR", ErrorMessage: "証明書の取得に失敗: connection refused & reset"},
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	report := GenerateJUnitReport(config, results)
+	if !strings.HasPrefix(report, xml.Header) {
+		t.Error("XML宣言が含まれていません")
+	}
+
+	var parsed junitTestSuites
+	if err := xml.Unmarshal([]byte(report), &parsed); err != nil {
+		t.Fatalf("XMLの解析に失敗: %v\n%s", err, report)
+	}
+
+	if parsed.Tests != 4 || parsed.Failures != 1 || parsed.Errors != 1 {
+		t.Errorf("件数が正しくありません。期待: tests=4 failures=1 errors=1, 実際: tests=%d failures=%d errors=%d", parsed.Tests, parsed.Failures, parsed.Errors)
+	}
+	if len(parsed.Suites) != 1 {
+		t.Fatalf("テストスイート数が正しくありません。期待: 1, 実際: %d", len(parsed.Suites))
+	}
+	suite := parsed.Suites[0]
+	if suite.Tests != 4 || suite.Failures != 1 || suite.Errors != 1 {
+		t.Errorf("テストスイートの件数が正しくありません。期待: tests=4 failures=1 errors=1, 実際: tests=%d failures=%d errors=%d", suite.Tests, suite.Failures, suite.Errors)
+	}
+	if len(suite.Cases) != len(results) {
+		t.Fatalf("テストケース数が正しくありません。期待: %d, 実際: %d", len(results), len(suite.Cases))
+	}
+
+	// CRITICALは失敗（failure）、チェックできなかったERRORはエラー（error）として報告されること
+	for i, testCase := range suite.Cases {
+		if testCase.Name != results[i].SiteName {
+			t.Errorf("テストケース名が正しくありません。期待: %s, 実際: %s", results[i].SiteName, testCase.Name)
+		}
+		if shouldFail := results[i].Status == "CRITICAL"; (testCase.Failure != nil) != shouldFail {
+			t.Errorf("%s の失敗の有無が正しくありません。期待: %v", testCase.Name, shouldFail)
+		}
+		if shouldError := results[i].Status == "ERROR"; (testCase.Error != nil) != shouldError {
+			t.Errorf("%s のエラーの有無が正しくありません。期待: %v", testCase.Name, shouldError)
+		}
+	}
+	if !strings.Contains(report, `<error message="証明書の取得に失敗: connection refused &amp; reset" type="ERROR">`) {
+		t.Errorf("error要素が含まれていません:\n%s", report)
+	}
+
+	// エラーメッセージがそのままエラーのメッセージになること
+	if message := suite.Cases[3].Error.Message; message != results[3].ErrorMessage {
+		t.Errorf("エラーのメッセージが正しくありません。期待: %s, 実際: %s", results[3].ErrorMessage, message)
+	}
+	if !strings.Contains(suite.Cases[2].Failure.Message, "残り5日") {
+		t.Errorf("CRITICALの失敗のメッセージに残り日数が含まれていません: %s", suite.Cases[2].Failure.Message)
+	}
+	if suite.Cases[3].ClassName != "cert-checker.error.example.com:8443" {
+		t.Errorf("クラス名が正しくありません: %s", suite.Cases[3].ClassName)
+	}
+	if !strings.Contains(suite.Cases[0].SystemOut, "有効期限終了: 2026-03-01 12:00:00 JST") {
+		t.Errorf("有効期限の表示が正しくありません: %s", suite.Cases[0].SystemOut)
+	}
+}
+
+// TestGenerateJUnitReportDateFormat report.timezoneとreport.date_formatがJUnitレポートの日時に反映されることのテスト
+func TestGenerateJUnitReportDateFormat(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Report.DateFormat = "02/01/2006 15:04"
+	config.location = time.UTC
+	results := []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.example.com", Port: 443, Issuer: "GlobalSign", NotAfter: time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC), DaysRemaining: 5, Status: "CRITICAL"},
+	}
+
+	var parsed junitTestSuites
+	if err := xml.Unmarshal([]byte(GenerateJUnitReport(config, results)), &parsed); err != nil {
+		t.Fatalf("XMLの解析に失敗: %v", err)
+	}
+	suite := parsed.Suites[0]
+	if !strings.Contains(suite.Cases[0].Failure.Text, "有効期限終了: 01/03/2026 03:00") {
+		t.Errorf("有効期限がreport.date_formatの書式で表示されていません: %s", suite.Cases[0].Failure.Text)
+	}
+	// timestamp属性はreport.date_formatに関係なくISO 8601とする
+	if _, err := time.Parse("2006-01-02T15:04:05", suite.Timestamp); err != nil {
+		t.Errorf("timestamp属性の形式が正しくありません: %s", suite.Timestamp)
+	}
+}
+
+// TestGenerateJUnitReportEnglish report.languageにenを指定した場合に英語で出力されることのテスト
+func TestGenerateJUnitReportEnglish(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Report.Language = "en"
+	config.location = time.UTC
+	notAfter := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	results := []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.example.com", Port: 443, Issuer: "GlobalSign", NotAfter: notAfter, DaysRemaining: 5, Status: "CRITICAL"},
+		{SiteName: "Expired Site", URL: "expired.example.com", Port: 443, Issuer: "GlobalSign", NotAfter: notAfter, DaysRemaining: -3, Expired: true, Status: "CRITICAL"},
+		{SiteName: "Error Site", URL: "error.example.com", Port: 443, Status: "ERROR", ErrorMessage: "connection refused"},
+	}
+
+	var parsed junitTestSuites
+	if err := xml.Unmarshal([]byte(GenerateJUnitReport(config, results)), &parsed); err != nil {
+		t.Fatalf("XMLの解析に失敗: %v", err)
+	}
+	cases := parsed.Suites[0].Cases
+
+	expectedMessages := []string{"The certificate expires in 5 days", "The certificate expired 3 days ago"}
+	for i, expected := range expectedMessages {
+		if message := cases[i].Failure.Message; message != expected {
+			t.Errorf("失敗のメッセージが正しくありません。期待: %s, 実際: %s", expected, message)
+		}
+	}
+	if message := cases[2].Error.Message; message != "connection refused" {
+		t.Errorf("エラーのメッセージが正しくありません。期待: connection refused, 実際: %s", message)
+	}
+	expected := "Status: CRITICAL\nIssuer: GlobalSign\nValid Until: 2026-03-01 03:00:00 UTC\nDays Remaining: 5 days"
+	if cases[0].SystemOut != expected {
+		t.Errorf("概要が正しくありません。\n期待: %q\n実際: %q", expected, cases[0].SystemOut)
+	}
+	if expected := "Status: ERROR\nError: connection refused"; cases[2].SystemOut != expected {
+		t.Errorf("エラーの概要が正しくありません。\n期待: %q\n実際: %q", expected, cases[2].SystemOut)
+	}
+}
@@ -0,0 +1,59 @@
+package certchecker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// markdownStatusEmoji ステータスごとにMarkdownレポートに表示する絵文字
+var markdownStatusEmoji = map[string]string{
+	"OK":       "✅",
+	"WARNING":  "⚠️",
+	"CRITICAL": "🚨",
+	"ERROR":    "❌",
+}
+
+// markdownCellEscaper 表のセルを崩さないよう、パイプと改行をエスケープする
+var markdownCellEscaper = strings.NewReplacer("|", `\|`, "\r\n", " ", "\n", " ")
+
+// GenerateMarkdownReport Markdown形式のレポートを生成
+// Wikiやプルリクエストの説明にそのまま貼り付けられるよう、サマリーとサイトごとの表を出力する
+// 日時はreport.timezoneのタイムゾーン、report.date_formatの書式で表示する（有効期限は省略時は日付のみ）
+// 見出し・項目名はreport.languageの表記を使用する
+func GenerateMarkdownReport(config *Config, results []CertInfo) string {
+	summary := summarizeResults(results)
+
+	var sb strings.Builder
+	sb.WriteString("## " + config.message("title") + "\n\n")
+	sb.WriteString(fmt.Sprintf(config.message("check_time"), config.formatTime(time.Now())) + "\n\n")
+	sb.WriteString(fmt.Sprintf(config.message("sites_markdown"),
+		summary.Total, summary.OK, summary.Warning, summary.Critical, summary.Error) + "\n\n")
+
+	sb.WriteString("| |")
+	for _, key := range []string{"site_name", "url", "status", "issuer", "subject", "valid_until", "days_remaining", "problems"} {
+		sb.WriteString(" " + config.message(key) + " |")
+	}
+	sb.WriteString("\n")
+	sb.WriteString("|---|---|---|---|---|---|---|---|---|\n")
+	for _, cert := range results {
+		cells := []string{
+			markdownStatusEmoji[cert.Status],
+			markdownCellEscaper.Replace(cert.SiteName),
+			markdownCellEscaper.Replace(displayAddress(cert.URL, cert.Port)),
+			cert.Status,
+			"", "", "", "",
+			// 証明書を取得できなかった理由や、WARNING・CRITICALの原因となった問題（OKの場合は空）
+			markdownCellEscaper.Replace(cert.ErrorMessage),
+		}
+		if cert.Status != "ERROR" {
+			cells[4] = markdownCellEscaper.Replace(cert.Issuer)
+			cells[5] = markdownCellEscaper.Replace(cert.Subject)
+			cells[6] = config.formatTimeOr(cert.NotAfter, "2006-01-02")
+			cells[7] = fmt.Sprintf(config.message("days"), cert.DaysRemaining)
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,132 @@
+package certchecker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateMarkdownReport Markdownレポート生成のテスト
+func TestGenerateMarkdownReport(t *testing.T) {
+	results := []CertInfo{
+		{
+			SiteName:      "Example Site",
+			URL:           "example.com",
+			Port:          443,
+			Issuer:        "CN=Example CA|G2,O=Example",
+			Subject:       "CN=example.com",
+			NotAfter:      time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC),
+			DaysRemaining: 20,
+			Status:        "WARNING",
+		},
+		{
+			SiteName:      "Critical Site",
+			URL:           "critical.example.com",
+			Port:          443,
+			Issuer:        "CN=Example CA",
+			Subject:       "CN=critical.example.com",
+			NotAfter:      time.Date(2026, 6, 1, 3, 0, 0, 0, time.UTC),
+			DaysRemaining: 90,
+			Status:        "CRITICAL",
+			ErrorMessage:  "MISMATCH: 証明書はホスト名 critical.example.com に対して有効ではありません; 証明書が失効しています (OCSP|CRL)",
+		},
+		{
+			SiteName:     "Error Site",
+			URL:          "error.example.com",
+			Port:         8443,
+			Status:       "ERROR",
+			ErrorMessage: "証明書の取得に失敗: a | b",
+		},
+	}
+
+	report := GenerateMarkdownReport(&Config{}, results)
+
+	// サマリー行
+	if !strings.Contains(report, "**サイト数: 3**（✅ OK: 0 / ⚠️ WARNING: 1 / 🚨 CRITICAL: 1 / ❌ ERROR: 1）") {
+		t.Errorf("サマリー行が含まれていません:\n%s", report)
+	}
+
+	// 表のヘッダーと区切り行
+	if !strings.Contains(report, "| | サイト名 | URL | ステータス | 発行者 | 主体者 | 有効期限終了 | 残り日数 | 問題 |\n|---|---|---|---|---|---|---|---|---|\n") {
+		t.Errorf("表のヘッダーが含まれていません:\n%s", report)
+	}
+
+	// パイプがエスケープされ、すべての行の列数がそろっていること
+	// CRITICALやERRORの行には、原因となった問題やエラーの内容を表示する
+	expectedRows := []string{
+		`| ⚠️ | Example Site | example.com:443 | WARNING | CN=Example CA\|G2,O=Example | CN=example.com | 2026-03-01 | 20日 |  |`,
+		`| 🚨 | Critical Site | critical.example.com:443 | CRITICAL | CN=Example CA | CN=critical.example.com | 2026-06-01 | 90日 | MISMATCH: 証明書はホスト名 critical.example.com に対して有効ではありません; 証明書が失効しています (OCSP\|CRL) |`,
+		`| ❌ | Error Site | error.example.com:8443 | ERROR |  |  |  |  | 証明書の取得に失敗: a \| b |`,
+	}
+	for _, row := range expectedRows {
+		if !strings.Contains(report, row+"\n") {
+			t.Errorf("表の行が正しくありません。期待: %s\n実際:\n%s", row, report)
+		}
+	}
+	for _, line := range strings.Split(report, "\n") {
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+		if columns := strings.Count(strings.ReplaceAll(line, `\|`, ""), "|"); columns != 10 {
+			t.Errorf("列数が正しくありません。期待: 10, 実際: %d (%s)", columns, line)
+		}
+	}
+}
+
+// TestGenerateMarkdownReportDateFormat report.timezoneとreport.date_formatがMarkdownレポートの日時に反映されることのテスト
+func TestGenerateMarkdownReportDateFormat(t *testing.T) {
+	results := []CertInfo{
+		{SiteName: "Example Site", URL: "example.com", Port: 443, NotAfter: time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC), DaysRemaining: 20, Status: "WARNING"},
+	}
+
+	testCases := []struct {
+		name       string
+		dateFormat string
+		location   *time.Location
+		expected   string
+	}{
+		// 省略時は日付のみ（JSTでは翌日になる）
+		{name: "標準", expected: "| 2026-03-02 |"},
+		{name: "タイムゾーン", location: time.UTC, expected: "| 2026-03-01 |"},
+		{name: "書式", dateFormat: "02/01/2006 15:04", location: time.UTC, expected: "| 01/03/2026 20:00 |"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := &Config{}
+			config.Report.DateFormat = tc.dateFormat
+			config.location = tc.location
+			report := GenerateMarkdownReport(config, results)
+			if !strings.Contains(report, tc.expected) {
+				t.Errorf("有効期限の表示が正しくありません。期待: %s\n実際:\n%s", tc.expected, report)
+			}
+		})
+	}
+}
+
+// TestGenerateMarkdownReportEnglish report.languageにenを指定した場合に英語で出力されることのテスト
+func TestGenerateMarkdownReportEnglish(t *testing.T) {
+	config := &Config{}
+	config.Report.Language = "en"
+	results := []CertInfo{
+		{SiteName: "Example Site", URL: "example.com", Port: 443, Issuer: "Example CA", Subject: "CN=example.com", NotAfter: time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC), DaysRemaining: 20, Status: "WARNING"},
+	}
+
+	report := GenerateMarkdownReport(config, results)
+	for _, expected := range []string{
+		"## SSL Certificate Expiry Check Results\n",
+		"Checked at: ",
+		"**Sites: 1** (✅ OK: 0 / ⚠️ WARNING: 1 / 🚨 CRITICAL: 0 / ❌ ERROR: 0)",
+		"| | Site | URL | Status | Issuer | Subject | Valid Until | Days Remaining | Problems |\n",
+		"| 2026-03-01 | 20 days |  |\n",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("英語のMarkdownレポートに %q が含まれていません:\n%s", expected, report)
+		}
+	}
+	for _, japanese := range []string{"サイト", "日時", "日 |"} {
+		if strings.Contains(report, japanese) {
+			t.Errorf("英語のMarkdownレポートに日本語 %q が含まれています:\n%s", japanese, report)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package certchecker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Nagiosプラグインの終了コード
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+// nagiosStates ステータスに対応するNagiosの状態と終了コード
+// 証明書を取得できなかった場合（ERROR）は状態を判定できないためUNKNOWNとする
+var nagiosStates = map[string]struct {
+	label string
+	code  int
+}{
+	"OK":       {"OK", nagiosOK},
+	"WARNING":  {"WARNING", nagiosWarning},
+	"CRITICAL": {"CRITICAL", nagiosCritical},
+	"ERROR":    {"UNKNOWN", nagiosUnknown},
+}
+
+// nagiosPrecedence Nagiosでの状態の優先順（深刻なものから順）
+// 終了コードの大小ではなく、CRITICAL > WARNING > UNKNOWN > OK の順で全体の状態を決める
+var nagiosPrecedence = []string{"CRITICAL", "WARNING", "ERROR", "OK"}
+
+// nagiosWorstStatus Nagiosの優先順で最も深刻なステータスを返す（結果がない場合はOK）
+func nagiosWorstStatus(results []CertInfo) string {
+	for _, status := range nagiosPrecedence {
+		for _, result := range results {
+			if result.Status == status {
+				return status
+			}
+		}
+	}
+	return "OK"
+}
+
+// GenerateNagiosReport Nagios/Icingaのプラグイン形式で1行のサマリーを生成し、終了コードとともに返す
+// 終了コードはNagiosの優先順で最も深刻なステータスに対応する（CRITICAL=2 > WARNING=1 > ERROR=3（UNKNOWN） > OK=0）
+// パイプ以降のパフォーマンスデータには、サイトごとの残り日数と判定に使用したしきい値を出力する
+func GenerateNagiosReport(config *Config, results []CertInfo) (string, int) {
+	worst := nagiosWorstStatus(results)
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	var parts []string
+	for _, status := range nagiosPrecedence {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], strings.ToLower(status)))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "0 sites")
+	}
+
+	var perfdata []string
+	for _, result := range results {
+		if result.Status == "ERROR" {
+			continue
+		}
+		// ラベル内のシングルクォートは2つ重ねてエスケープする
+		label := strings.ReplaceAll(result.SiteName, "'", "''")
+		// しきい値を単に N とすると「Nを超えたら警告」の意味になるため、
+		// 残り日数がしきい値以下になったら警告する @~:N（負の無限大からNまでの範囲内で警告）の形式で出力する
+		perfdata = append(perfdata, fmt.Sprintf("'%s'=%dd;@~:%d;@~:%d", label, result.DaysRemaining, result.WarningDays, result.CriticalDays))
+	}
+
+	state := nagiosStates[worst]
+	line := fmt.Sprintf("SSL %s - %s", state.label, strings.Join(parts, ", "))
+	if len(perfdata) > 0 {
+		line += " | " + strings.Join(perfdata, " ")
+	}
+	return line, state.code
+}
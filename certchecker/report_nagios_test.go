@@ -0,0 +1,73 @@
+package certchecker
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateNagiosReport Nagios形式の出力と終了コードのテスト
+func TestGenerateNagiosReport(t *testing.T) {
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+
+	testCases := []struct {
+		name           string
+		statuses       []string
+		expectedCode   int
+		expectedPrefix string
+	}{
+		{name: "結果なし", statuses: nil, expectedCode: 0, expectedPrefix: "SSL OK - 0 sites"},
+		{name: "すべてOK", statuses: []string{"OK", "OK"}, expectedCode: 0, expectedPrefix: "SSL OK - 2 ok"},
+		{name: "警告を含む", statuses: []string{"OK", "WARNING"}, expectedCode: 1, expectedPrefix: "SSL WARNING - 1 warning, 1 ok"},
+		{name: "緊急と警告を含む", statuses: []string{"WARNING", "CRITICAL", "OK", "CRITICAL"}, expectedCode: 2, expectedPrefix: "SSL CRITICAL - 2 critical, 1 warning, 1 ok"},
+		{name: "緊急とエラーを含む", statuses: []string{"CRITICAL", "ERROR"}, expectedCode: 2, expectedPrefix: "SSL CRITICAL - 1 critical, 1 error"},
+		{name: "警告とエラーを含む", statuses: []string{"ERROR", "WARNING", "OK"}, expectedCode: 1, expectedPrefix: "SSL WARNING - 1 warning, 1 error, 1 ok"},
+		{name: "エラーのみ", statuses: []string{"OK", "ERROR"}, expectedCode: 3, expectedPrefix: "SSL UNKNOWN - 1 error, 1 ok"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []CertInfo
+			for i, status := range tc.statuses {
+				results = append(results, CertInfo{SiteName: "site" + string(rune('a'+i)), Status: status, DaysRemaining: 10})
+			}
+
+			line, code := GenerateNagiosReport(config, results)
+			if code != tc.expectedCode {
+				t.Errorf("終了コードが正しくありません。期待: %d, 実際: %d", tc.expectedCode, code)
+			}
+			if !strings.HasPrefix(line, tc.expectedPrefix) {
+				t.Errorf("出力が正しくありません。期待: %s..., 実際: %s", tc.expectedPrefix, line)
+			}
+			if strings.Contains(line, "\n") {
+				t.Errorf("出力が1行ではありません: %q", line)
+			}
+		})
+	}
+}
+
+// TestGenerateNagiosReportPerfdata Nagios形式のパフォーマンスデータのテスト
+func TestGenerateNagiosReportPerfdata(t *testing.T) {
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+
+	results := []CertInfo{
+		{SiteName: "Google", Status: "OK", DaysRemaining: 48, WarningDays: 30, CriticalDays: 7},
+		{SiteName: "Bob's Site", Status: "WARNING", DaysRemaining: 20, WarningDays: 30, CriticalDays: 7},
+		{SiteName: "Payment", Status: "WARNING", DaysRemaining: 50, WarningDays: 60, CriticalDays: 14},
+		{SiteName: "Down", Status: "ERROR"},
+	}
+
+	line, _ := GenerateNagiosReport(config, results)
+	_, perfdata, found := strings.Cut(line, " | ")
+	if !found {
+		t.Fatalf("パフォーマンスデータがありません: %s", line)
+	}
+	// 残り日数がしきい値以下になったときに警告する範囲（@~:N）で出力する
+	expected := "'Google'=48d;@~:30;@~:7 'Bob''s Site'=20d;@~:30;@~:7 'Payment'=50d;@~:60;@~:14"
+	if perfdata != expected {
+		t.Errorf("パフォーマンスデータが正しくありません。期待: %s, 実際: %s", expected, perfdata)
+	}
+}
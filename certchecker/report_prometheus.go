@@ -0,0 +1,43 @@
+package certchecker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prometheusLabelEscaper Prometheusのラベル値で必要なエスケープ（バックスラッシュ、ダブルクォート、改行）
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusLabels サイトを識別するラベルを作成
+func prometheusLabels(cert CertInfo) string {
+	return fmt.Sprintf(`site="%s",url="%s"`,
+		prometheusLabelEscaper.Replace(cert.SiteName),
+		prometheusLabelEscaper.Replace(displayAddress(cert.URL, cert.Port)))
+}
+
+// GeneratePrometheusReport Prometheusのテキスト形式でメトリクスを生成
+// 証明書を取得できなかったサイトは有効期限のメトリクスを出力せず、ssl_cert_check_successを0とする
+func GeneratePrometheusReport(results []CertInfo) string {
+	var sb strings.Builder
+
+	sb.WriteString("# HELP ssl_cert_expiry_days Days remaining until the certificate expires.\n")
+	sb.WriteString("# TYPE ssl_cert_expiry_days gauge\n")
+	for _, cert := range results {
+		if cert.Status == "ERROR" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("ssl_cert_expiry_days{%s} %d\n", prometheusLabels(cert), cert.DaysRemaining))
+	}
+
+	sb.WriteString("# HELP ssl_cert_check_success Whether the certificate could be retrieved (1) or not (0).\n")
+	sb.WriteString("# TYPE ssl_cert_check_success gauge\n")
+	for _, cert := range results {
+		success := 1
+		if cert.Status == "ERROR" {
+			success = 0
+		}
+		sb.WriteString(fmt.Sprintf("ssl_cert_check_success{%s} %d\n", prometheusLabels(cert), success))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package certchecker
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGeneratePrometheusReport Prometheus形式のメトリクス生成のテスト
+func TestGeneratePrometheusReport(t *testing.T) {
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "example.com", Port: 443, DaysRemaining: 45, Status: "OK"},
+		{SiteName: `Quoted "Site" \ test`, URL: "quoted.example.com", Port: 8443, DaysRemaining: -3, Status: "CRITICAL"},
+		{SiteName: "Error Site", URL: "error.example.com", Port: 443, Status: "ERROR"},
+	}
+
+	report := GeneratePrometheusReport(results)
+
+	expectedLines := []string{
+		"# TYPE ssl_cert_expiry_days gauge",
+		`ssl_cert_expiry_days{site="Test Site",url="example.com:443"} 45`,
+		`ssl_cert_expiry_days{site="Quoted \"Site\" \\ test",url="quoted.example.com:8443"} -3`,
+		"# TYPE ssl_cert_check_success gauge",
+		`ssl_cert_check_success{site="Test Site",url="example.com:443"} 1`,
+		`ssl_cert_check_success{site="Error Site",url="error.example.com:443"} 0`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(report, line+"\n") {
+			t.Errorf("メトリクスに %q が含まれていません\n%s", line, report)
+		}
+	}
+
+	// 証明書を取得できなかったサイトの有効期限は出力しない
+	if strings.Contains(report, `ssl_cert_expiry_days{site="Error Site"`) {
+		t.Error("エラーのサイトの有効期限が出力されています")
+	}
+
+	// コメント以外の行は「メトリクス名{ラベル} 値」の形式
+	for _, line := range strings.Split(strings.TrimSpace(report), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, "ssl_cert_") || !strings.Contains(line, "} ") {
+			t.Errorf("メトリクスの形式が正しくありません: %s", line)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package certchecker
+
+import (
+	"sort"
+	"strings"
+)
+
+// レポートの並び順（report.sort）
+const (
+	sortDaysAsc = "days_asc" // 残り日数の少ない順（期限切れを先頭、ERRORは末尾）
+	sortStatus  = "status"   // ステータスの深刻な順（同じステータスは残り日数の少ない順）
+	sortName    = "name"     // サイト名の順
+)
+
+// sortResults 指定した並び順で並べ替えた結果のコピーを返す（未指定の場合は設定ファイルの順序のまま）
+func sortResults(results []CertInfo, order string) []CertInfo {
+	sorted := make([]CertInfo, len(results))
+	copy(sorted, results)
+
+	var less func(a, b CertInfo) bool
+	switch order {
+	case sortDaysAsc:
+		// ERRORは残り日数が分からないため、日数順の並びに混ざらないよう末尾にまとめる
+		less = func(a, b CertInfo) bool {
+			if (a.Status == "ERROR") != (b.Status == "ERROR") {
+				return b.Status == "ERROR"
+			}
+			return a.DaysRemaining < b.DaysRemaining
+		}
+	case sortStatus:
+		less = func(a, b CertInfo) bool {
+			if statusSeverity[a.Status] != statusSeverity[b.Status] {
+				return statusSeverity[a.Status] > statusSeverity[b.Status]
+			}
+			return a.DaysRemaining < b.DaysRemaining
+		}
+	case sortName:
+		less = func(a, b CertInfo) bool {
+			return strings.ToLower(a.SiteName) < strings.ToLower(b.SiteName)
+		}
+	default:
+		return sorted
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,71 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"strings"
+	"testing"
+)
+
+// TestSortResults 並び順ごとの並べ替えのテスト
+func TestSortResults(t *testing.T) {
+	results := []CertInfo{
+		{SiteName: "charlie", Status: "OK", DaysRemaining: 80},
+		{SiteName: "Down", Status: "ERROR"},
+		{SiteName: "alpha", Status: "WARNING", DaysRemaining: 20},
+		{SiteName: "Expired", Status: "CRITICAL", DaysRemaining: -3, Expired: true},
+		{SiteName: "bravo", Status: "CRITICAL", DaysRemaining: 5},
+		{SiteName: "Mismatch", Status: "CRITICAL", DaysRemaining: 60},
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	testCases := []struct {
+		order    string
+		expected []string
+	}{
+		{"", []string{"charlie", "Down", "alpha", "Expired", "bravo", "Mismatch"}},
+		{sortDaysAsc, []string{"Expired", "bravo", "alpha", "Mismatch", "charlie", "Down"}},
+		{sortStatus, []string{"Down", "Expired", "bravo", "Mismatch", "alpha", "charlie"}},
+		{sortName, []string{"alpha", "bravo", "charlie", "Down", "Expired", "Mismatch"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.order, func(t *testing.T) {
+			sorted := sortResults(results, tc.order)
+			var names []string
+			for _, result := range sorted {
+				names = append(names, result.SiteName)
+			}
+			if strings.Join(names, ",") != strings.Join(tc.expected, ",") {
+				t.Errorf("並び順が正しくありません。期待: %v, 実際: %v", tc.expected, names)
+			}
+		})
+	}
+
+	// 元のスライスは変更しない
+	if results[0].SiteName != "charlie" || results[1].SiteName != "Down" {
+		t.Error("元の結果の順序が変更されています")
+	}
+}
+
+// TestGenerateTextReportSorted テキストレポートが指定した並び順で出力されることのテスト
+func TestGenerateTextReportSorted(t *testing.T) {
+	config := &Config{}
+	config.Report.Sort = sortDaysAsc
+
+	results := []CertInfo{
+		{SiteName: "Later", Status: "OK", DaysRemaining: 90},
+		{SiteName: "Sooner", Status: "WARNING", DaysRemaining: 10},
+	}
+
+	report := GenerateTextReport(config, results)
+	if strings.Index(report, "サイト名: Sooner") > strings.Index(report, "サイト名: Later") {
+		t.Errorf("残り日数の少ないサイトが先に出力されていません:\n%s", report)
+	}
+	report = GenerateHTMLReport(config, results)
+	if strings.Index(report, "<td>Sooner</td>") > strings.Index(report, "<td>Later</td>") {
+		t.Errorf("HTMLレポートで残り日数の少ないサイトが先に出力されていません")
+	}
+}
@@ -0,0 +1,114 @@
+package certchecker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultTextReportTemplate テキストレポートの標準の形式
+// report.templateで独自のテンプレートを指定する場合の参考として、同じデータと関数を使用して記述している
+// 見出し・項目名・定型文はmsgでreport.languageの表記（messages.go）を出力する
+const defaultTextReportTemplate = `{{repeat "=" 80}}
+{{msg "title"}}
+{{msg "check_time" .CheckTime}}
+{{msg "sites" .Summary.Total .Summary.OK .Summary.Warning .Summary.Critical .Summary.Error}}
+{{if .OmittedOK}}{{msg "omitted_ok" .OmittedOK}}
+{{end}}{{repeat "=" 80}}
+
+{{range .Results}}{{msg "site_name"}}: {{.SiteName}}
+{{msg "url"}}: {{address .URL .Port}}
+{{msg "status"}}: {{status .Status}}{{if .Muted}}{{msg "muted" .MutedUntil}}{{end}}
+{{if ne .Status "ERROR"}}{{msg "issuer"}}: {{.Issuer}}
+{{msg "signature_algorithm"}}: {{.SignatureAlgorithm}}
+{{msg "public_key"}}: {{msg "key_bits" .KeyType .KeyBits}}
+{{msg "fingerprint"}}: {{.FingerprintSHA256}}
+{{msg "serial_number"}}: {{.SerialNumber}}
+{{if .TLSVersion}}{{msg "tls"}}: {{.TLSVersion}} ({{.CipherSuite}})
+{{end}}{{if .SelfSigned}}{{msg "self_signed_label"}}: {{msg "yes"}}
+{{end}}{{if .RevocationStatus}}{{msg "revocation_status"}}: {{.RevocationStatus}}
+{{end}}{{if .MustStaple}}{{if .OCSPStapled}}{{msg "must_staple" (msg "ocsp_stapled")}}{{else}}{{msg "must_staple" (msg "ocsp_not_stapled")}}{{end}}
+{{end}}{{msg "subject"}}: {{.Subject}}
+{{if .SANs}}{{msg "san"}}: {{join .SANs ", "}}
+{{end}}{{if .ExtKeyUsages}}{{msg "ext_key_usage"}}: {{join .ExtKeyUsages ", "}}
+{{end}}{{if .CAARecords}}{{msg "caa" .CAADomain}}: {{join .CAARecords ", "}}
+{{end}}{{msg "not_before"}}: {{date .NotBefore}}{{if .NotYetValid}}{{msg "not_yet_valid"}}{{end}}
+{{msg "valid_until"}}: {{date .NotAfter}}
+{{msg "validity_period"}}: {{msg "days" .ValidityDays}}
+{{msg "days_remaining"}}: {{msg "days" .DaysRemaining}}{{if .Expired}}{{msg "expired"}}{{end}}{{with delta .DaysRemainingDelta}}{{msg "delta" .}}{{end}}
+{{if .DaysRemainingStalled}}{{msg "stalled"}}
+{{end}}{{if .LeafCertificates}}{{msg "leaf_certificates"}}
+{{range .LeafCertificates}}  {{msg "leaf_certificate" .KeyType .KeyBits (date .NotAfter) .DaysRemaining}}
+{{end}}{{end}}{{if gt .Attempts 1}}{{msg "retry" (sub .Attempts 1)}}
+{{end}}{{if .CheckDuration}}{{msg "duration"}}: {{duration .CheckDuration}}
+{{end}}{{if .ErrorMessage}}{{msg "warning"}}: {{.ErrorMessage}}
+{{end}}{{if and $.ShowChain .Chain}}{{msg "chain"}}
+{{range $i, $link := .Chain}}  [{{$i}}] {{msg "chain_link" $link.Subject $link.Issuer (date $link.NotAfter)}}
+{{end}}{{end}}{{else}}{{msg "error"}}: {{.ErrorMessage}}
+{{end}}{{repeat "-" 80}}
+{{end}}`
+
+// textReportData テキストレポートのテンプレートに渡すデータ
+type textReportData struct {
+	CheckTime string      // チェック日時（report.timezoneのタイムゾーン）
+	Summary   jsonSummary // ステータスごとのサイト数（省略したOKの結果も含む）
+	OmittedOK int         // report.only_problemsで省略したOKの結果の数
+	ShowChain bool        // report.show_chainの設定値
+	Results   []CertInfo  // レポートに表示する結果
+}
+
+// textReportFuncs テキストレポートのテンプレートで使用できる関数
+// 日時の書式や色付けは実行時の設定に依存するため、renderTextReportで置き換える
+var textReportFuncs = template.FuncMap{
+	"date":     func(t time.Time) string { return t.In(JST).Format(defaultDateFormat) },
+	"address":  displayAddress,
+	"join":     strings.Join,
+	"repeat":   strings.Repeat,
+	"lower":    strings.ToLower,
+	"upper":    strings.ToUpper,
+	"sub":      func(a, b int) int { return a - b },
+	"status":   func(status string) string { return status },
+	"duration": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+	"delta":    func(delta *int) string { return daysRemainingDeltaLabel(languageJapanese, delta) },
+	"msg":      func(key string, args ...any) string { return reportMessagef(languageJapanese, key, args...) },
+}
+
+// builtinTextReportTemplate 標準の形式を解析したテンプレート
+var builtinTextReportTemplate = template.Must(template.New("text").Funcs(textReportFuncs).Parse(defaultTextReportTemplate))
+
+// loadTextReportTemplate report.templateで指定したテンプレートファイルを読み込む
+func loadTextReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return template.New("text").Funcs(textReportFuncs).Parse(string(data))
+}
+
+// renderTextReport テンプレートでテキストレポートを生成する
+// colorがtrueの場合は、statusで出力するステータスをANSIエスケープシーケンスで色付けする
+// dateで出力する日時はreport.timezone・report.date_formatに、msgとdeltaで出力する表記はreport.languageに従う
+func renderTextReport(tmpl *template.Template, data textReportData, config *Config, color bool) (string, error) {
+	// 並行して生成しても影響しないよう、複製したテンプレートの関数を置き換える
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	funcs := template.FuncMap{
+		"date":  config.formatTime,
+		"delta": func(delta *int) string { return daysRemainingDeltaLabel(config.reportLanguage(), delta) },
+		"msg":   func(key string, args ...any) string { return reportMessagef(config.reportLanguage(), key, args...) },
+	}
+	if color {
+		funcs["status"] = colorizeStatus
+	}
+	tmpl.Funcs(funcs)
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("テンプレートの実行に失敗: %v", err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,145 @@
+package certchecker
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGenerateTextReportTemplate report.templateで指定したテンプレートでテキストレポートを生成するテスト
+func TestGenerateTextReportTemplate(t *testing.T) {
+	dir := t.TempDir()
+	templatePath := filepath.Join(dir, "report.tmpl")
+	tmpl := `{{.Summary.Total}}件中{{.Summary.Warning}}件が警告
+{{range .Results}}{{.SiteName}} ({{address .URL .Port}}) {{.Status}} {{.DaysRemaining}}日 {{date .NotAfter}}
+{{end}}`
+	if err := os.WriteFile(templatePath, []byte(tmpl), 0600); err != nil {
+		t.Fatalf("テンプレートの書き込みに失敗: %v", err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("report:\n  template: "+templatePath+"\n  timezone: UTC\n"), 0600); err != nil {
+		t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	notAfter := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	results := []CertInfo{
+		{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK", DaysRemaining: 60, NotAfter: notAfter},
+		{SiteName: "Admin", URL: "admin.example.com", Port: 8443, Status: "WARNING", DaysRemaining: 20, NotAfter: notAfter},
+	}
+
+	expected := "2件中1件が警告\n" +
+		"Example (example.com:443) OK 60日 2026-03-01 12:00:00 UTC\n" +
+		"Admin (admin.example.com:8443) WARNING 20日 2026-03-01 12:00:00 UTC\n"
+	if report := GenerateTextReport(config, results); report != expected {
+		t.Errorf("テンプレートの出力が一致しません\n期待: %q\n実際: %q", expected, report)
+	}
+}
+
+// TestGenerateTextReportTemplateFallback テンプレートの実行に失敗した場合に標準の形式で出力するテスト
+func TestGenerateTextReportTemplateFallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(path, []byte("{{.Unknown}}"), 0600); err != nil {
+		t.Fatalf("テンプレートの書き込みに失敗: %v", err)
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	tmpl, err := loadTextReportTemplate(path)
+	if err != nil {
+		t.Fatalf("テンプレートの読み込みに失敗: %v", err)
+	}
+	config := &Config{textTemplate: tmpl}
+
+	report := GenerateTextReport(config, []CertInfo{{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK"}})
+	if !strings.Contains(report, "SSL証明書有効期限チェック結果") || !strings.Contains(report, "サイト名: Example") {
+		t.Errorf("標準の形式で出力されていません: %s", report)
+	}
+}
+
+// TestLoadConfigInvalidTemplate 解析できないテンプレートや存在しないテンプレートのテスト
+func TestLoadConfigInvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	invalidPath := filepath.Join(dir, "invalid.tmpl")
+	if err := os.WriteFile(invalidPath, []byte("{{range .Results}}"), 0600); err != nil {
+		t.Fatalf("テンプレートの書き込みに失敗: %v", err)
+	}
+
+	for _, path := range []string{invalidPath, filepath.Join(dir, "missing.tmpl")} {
+		configPath := filepath.Join(dir, "config.yaml")
+		if err := os.WriteFile(configPath, []byte("report:\n  template: "+path+"\n"), 0600); err != nil {
+			t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+		}
+		if _, err := LoadConfig(configPath); err == nil {
+			t.Errorf("%s でエラーが発生しませんでした", filepath.Base(path))
+		}
+	}
+}
+
+// TestGenerateHTMLReportCustomCSS report.html_cssのCSSとreport.html_titleのタイトルでHTMLレポートを生成するテスト
+func TestGenerateHTMLReportCustomCSS(t *testing.T) {
+	dir := t.TempDir()
+	cssPath := filepath.Join(dir, "dark.css")
+	css := "body { background: #1e1e1e; color: #ddd; }\nth { background-color: #333; }\n"
+	if err := os.WriteFile(cssPath, []byte(css), 0600); err != nil {
+		t.Fatalf("CSSの書き込みに失敗: %v", err)
+	}
+	configPath := filepath.Join(dir, "config.yaml")
+	yaml := "report:\n  html_css: " + cssPath + "\n  html_title: \"証明書 <社内Wiki>\"\n"
+	if err := os.WriteFile(configPath, []byte(yaml), 0600); err != nil {
+		t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+	}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+
+	results := []CertInfo{{SiteName: "Example", URL: "example.com", Port: 443, Status: "OK", DaysRemaining: 60}}
+	report := GenerateHTMLReport(config, results)
+	for _, expected := range []string{
+		"body { background: #1e1e1e; color: #ddd; }",
+		"th { background-color: #333; }",
+		"<title>証明書 &lt;社内Wiki&gt;</title>",
+		"<h1>証明書 &lt;社内Wiki&gt;</h1>",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("HTMLレポートに %q が含まれていません:\n%s", expected, report)
+		}
+	}
+	if strings.Contains(report, "#4CAF50") {
+		t.Error("標準のスタイルが置き換えられていません")
+	}
+
+	// 指定しない場合は標準のスタイルとタイトル
+	report = GenerateHTMLReport(&Config{}, results)
+	if !strings.Contains(report, "th { background-color: #4CAF50; color: white; }") || !strings.Contains(report, "width: 100%;") {
+		t.Errorf("標準のスタイルが出力されていません:\n%s", report)
+	}
+	if !strings.Contains(report, "<h1>SSL証明書有効期限チェック結果</h1>") {
+		t.Error("標準のタイトルが出力されていません")
+	}
+
+	// styleタグを閉じる内容を含むCSSは読み込まない
+	if err := os.WriteFile(cssPath, []byte("body {}</STYLE><script>alert(1)</script>"), 0600); err != nil {
+		t.Fatalf("CSSの書き込みに失敗: %v", err)
+	}
+	if _, err := LoadConfig(configPath); err == nil {
+		t.Error("</style> を含むCSSでエラーが発生しませんでした")
+	}
+}
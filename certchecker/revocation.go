@@ -0,0 +1,291 @@
+package certchecker
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// revocationClient 失効確認（OCSP/CRL）に使用するHTTPクライアント
+var revocationClient = &http.Client{Timeout: defaultTimeout}
+
+// newRevocationClient プロキシ経由で失効確認を行うHTTPクライアントを作成する
+// dialerを指定した場合は証明書の取得と同じSOCKS5プロキシ経由で接続し、それ以外でproxyURLを指定した場合はそのHTTPプロキシを使用する
+func newRevocationClient(dialer contextDialer, proxyURL *url.URL) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if dialer != nil {
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	} else if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	return &http.Client{Timeout: defaultTimeout, Transport: transport}
+}
+
+// revocationHTTPClient 失効確認に使用するHTTPクライアントを返す
+// alert.socks_proxyやproxy_urlを設定した場合は、そのプロキシを経由するクライアントを返す
+func revocationHTTPClient(config *Config) *http.Client {
+	if config.revocationClient != nil {
+		return config.revocationClient
+	}
+	return revocationClient
+}
+
+// oidTLSFeature TLS Feature拡張（RFC 7633）のOID
+var oidTLSFeature = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 1, 24}
+
+// tlsFeatureStatusRequest TLS Feature拡張でOCSPステープリング（status_request）を示す値
+const tlsFeatureStatusRequest = 5
+
+// hasMustStaple 証明書にMust-Staple（TLS Feature拡張のstatus_request）が指定されているか
+func hasMustStaple(cert *x509.Certificate) bool {
+	for _, ext := range cert.Extensions {
+		if !ext.Id.Equal(oidTLSFeature) {
+			continue
+		}
+		var features []int
+		if _, err := asn1.Unmarshal(ext.Value, &features); err != nil {
+			return false
+		}
+		for _, feature := range features {
+			if feature == tlsFeatureStatusRequest {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkMustStaple Must-Stapleの証明書で、サーバーがOCSPレスポンスをステープルしているかを確認する
+// ステープルされていない場合、Must-Stapleに対応したクライアントは接続を拒否する
+func checkMustStaple(config *Config, info *CertInfo, leaf *x509.Certificate, ocspResponse []byte) {
+	info.MustStaple = hasMustStaple(leaf)
+	info.OCSPStapled = len(ocspResponse) > 0
+	if info.MustStaple && !info.OCSPStapled {
+		info.addProblem("WARNING", config.message("problem_must_staple"))
+	}
+}
+
+// checkOCSPRevocation OCSPで失効状態を確認し、結果をCertInfoに反映する
+// レスポンダーが指定されていない場合や問い合わせに失敗した場合はログに記録するのみで、ステータスは変更しない
+func checkOCSPRevocation(ctx context.Context, config *Config, info *CertInfo, certs []*x509.Certificate) {
+	leaf := certs[0]
+	if len(leaf.OCSPServer) == 0 {
+		LogDebugf("%s:%d - OCSPレスポンダーが指定されていないため失効確認をスキップします", info.URL, info.Port)
+		return
+	}
+	if len(certs) < 2 {
+		LogDebugf("%s:%d - 発行者の証明書が提示されていないためOCSPによる失効確認をスキップします", info.URL, info.Port)
+		return
+	}
+
+	status, err := queryOCSP(ctx, revocationHTTPClient(config), leaf, certs[1])
+	if err != nil {
+		LogWarnf("%s:%d - OCSPによる失効確認に失敗: %v", info.URL, info.Port, err)
+		return
+	}
+
+	info.RevocationStatus = status
+	if status == "REVOKED" {
+		info.Revoked = true
+		info.addProblem("CRITICAL", fmt.Sprintf(config.message("problem_revoked"), "OCSP"))
+	}
+}
+
+// queryOCSP リーフ証明書のOCSPレスポンダーに問い合わせ、GOOD/REVOKED/UNKNOWNのいずれかを返す
+func queryOCSP(ctx context.Context, client *http.Client, leaf, issuer *x509.Certificate) (string, error) {
+	reqData, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return "", fmt.Errorf("OCSPリクエストの作成に失敗: %v", err)
+	}
+
+	var lastErr error
+	for _, server := range leaf.OCSPServer {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server, bytes.NewReader(reqData))
+		if err != nil {
+			lastErr = fmt.Errorf("OCSPリクエストの作成に失敗: %v", err)
+			continue
+		}
+		req.Header.Set("Content-Type", "application/ocsp-request")
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("OCSPレスポンダーへの接続に失敗: %v", err)
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = fmt.Errorf("OCSPレスポンスの読み込みに失敗: %v", err)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("OCSPレスポンダーがエラーを返しました: %d", resp.StatusCode)
+			continue
+		}
+
+		ocspResp, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+		if err != nil {
+			lastErr = fmt.Errorf("OCSPレスポンスの解析に失敗: %v", err)
+			continue
+		}
+
+		switch ocspResp.Status {
+		case ocsp.Good:
+			return "GOOD", nil
+		case ocsp.Revoked:
+			return "REVOKED", nil
+		default:
+			return "UNKNOWN", nil
+		}
+	}
+
+	return "", lastErr
+}
+
+// crlCacheTTL CRLをキャッシュする最大の期間
+// 常駐時に新しく失効した証明書を検出できるよう、次回更新日時がこれより先でもこの期間が過ぎたら再取得する
+const crlCacheTTL = 1 * time.Hour
+
+// crlCache 配布ポイントのURLごとにダウンロード済みのCRLを保持する
+// 同じCAの証明書を多数チェックする場合でも、CRLのダウンロードは1回で済む
+// 期限切れのエントリや取得に失敗したエントリは次の取得時に削除するため、常駐してもキャッシュは増え続けない
+type crlCache struct {
+	mu      sync.Mutex
+	entries map[string]*crlCacheEntry
+}
+
+// crlCacheEntry キャッシュされたCRL
+type crlCacheEntry struct {
+	mu        sync.Mutex
+	crl       *x509.RevocationList
+	expiresAt time.Time
+}
+
+// crls CRLのキャッシュ
+var crls = newCRLCache()
+
+// newCRLCache 空のCRLキャッシュを作成
+func newCRLCache() *crlCache {
+	return &crlCache{entries: make(map[string]*crlCacheEntry)}
+}
+
+// get CRLを取得する（キャッシュが有効な場合はダウンロードしない）
+func (c *crlCache) get(ctx context.Context, client *http.Client, url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	now := time.Now()
+	c.mu.Lock()
+	c.evict(url, now)
+	entry, ok := c.entries[url]
+	if !ok {
+		entry = &crlCacheEntry{}
+		c.entries[url] = entry
+	}
+	c.mu.Unlock()
+
+	// 同じURLへの同時ダウンロードを防ぐため、エントリ単位でロックする
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.crl != nil && now.Before(entry.expiresAt) {
+		return entry.crl, nil
+	}
+
+	crl, err := fetchCRL(ctx, client, url, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.crl = crl
+	entry.expiresAt = now.Add(crlCacheTTL)
+	if !crl.NextUpdate.IsZero() && crl.NextUpdate.Before(entry.expiresAt) {
+		entry.expiresAt = crl.NextUpdate
+	}
+	return crl, nil
+}
+
+// evict 期限切れのCRLと取得に失敗したエントリを削除する（c.muを保持した状態で呼び出す）
+// ダウンロード中のエントリ（ロックを取得できないもの）と、これから取得するURLのエントリは残す
+func (c *crlCache) evict(keep string, now time.Time) {
+	for url, entry := range c.entries {
+		if url == keep || !entry.mu.TryLock() {
+			continue
+		}
+		if entry.crl == nil || !now.Before(entry.expiresAt) {
+			delete(c.entries, url)
+		}
+		entry.mu.Unlock()
+	}
+}
+
+// fetchCRL CRLをダウンロードし、発行者の署名を検証する
+func fetchCRL(ctx context.Context, client *http.Client, url string, issuer *x509.Certificate) (*x509.RevocationList, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("CRLのリクエストの作成に失敗: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("CRLのダウンロードに失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("CRL配布ポイントがエラーを返しました: %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("CRLの読み込みに失敗: %v", err)
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return nil, fmt.Errorf("CRLの解析に失敗: %v", err)
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		return nil, fmt.Errorf("CRLの署名の検証に失敗: %v", err)
+	}
+
+	return crl, nil
+}
+
+// checkCRLRevocation CRLで失効状態を確認し、結果をCertInfoに反映する
+// 配布ポイントが指定されていない場合やダウンロードに失敗した場合はログに記録するのみで、ステータスは変更しない
+func checkCRLRevocation(ctx context.Context, config *Config, info *CertInfo, certs []*x509.Certificate) {
+	leaf := certs[0]
+	if len(leaf.CRLDistributionPoints) == 0 {
+		LogDebugf("%s:%d - CRL配布ポイントが指定されていないため失効確認をスキップします", info.URL, info.Port)
+		return
+	}
+	if len(certs) < 2 {
+		LogDebugf("%s:%d - 発行者の証明書が提示されていないためCRLによる失効確認をスキップします", info.URL, info.Port)
+		return
+	}
+
+	for _, url := range leaf.CRLDistributionPoints {
+		crl, err := crls.get(ctx, revocationHTTPClient(config), url, certs[1])
+		if err != nil {
+			LogWarnf("%s:%d - CRLによる失効確認に失敗: %v", info.URL, info.Port, err)
+			continue
+		}
+
+		info.RevocationStatus = "GOOD"
+		for _, entry := range crl.RevokedCertificateEntries {
+			if entry.SerialNumber.Cmp(leaf.SerialNumber) == 0 {
+				info.RevocationStatus = "REVOKED"
+				info.Revoked = true
+				info.addProblem("CRITICAL", fmt.Sprintf(config.message("problem_revoked"), "CRL"))
+				return
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,493 @@
+package certchecker
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// newOCSPResponder 指定したステータスを返すOCSPレスポンダーを起動する
+func newOCSPResponder(t *testing.T, issuer *testCert, status *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		tmpl := ocsp.Response{
+			Status:       *status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Hour),
+			NextUpdate:   time.Now().Add(time.Hour),
+		}
+		if *status == ocsp.Revoked {
+			tmpl.RevokedAt = time.Now().Add(-time.Hour)
+		}
+		resp, err := ocsp.CreateResponse(issuer.cert, issuer.cert, tmpl, issuer.key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestCheckCertificateOCSP OCSPによる失効確認のテスト
+func TestCheckCertificateOCSP(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test OCSP CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+
+	status := ocsp.Good
+	responder := newOCSPResponder(t, &ca, &status)
+
+	leaf := newTestCert(t, &x509.Certificate{
+		Subject:    pkix.Name{CommonName: "ocsp.example.com"},
+		DNSNames:   []string{"ocsp.example.com"},
+		OCSPServer: []string{responder.URL},
+	}, &ca)
+	port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{leaf.tlsCertificate(ca)}})
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	testCases := []struct {
+		name           string
+		ocspStatus     int
+		expectedResult string
+		expectRevoked  bool
+	}{
+		{name: "有効な証明書", ocspStatus: ocsp.Good, expectedResult: "GOOD", expectRevoked: false},
+		{name: "失効した証明書", ocspStatus: ocsp.Revoked, expectedResult: "REVOKED", expectRevoked: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status = tc.ocspStatus
+
+			config := &Config{}
+			config.Alert.WarningDays = 30
+			config.Alert.CriticalDays = 7
+			config.Alert.CheckOCSP = true
+
+			result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "OCSP"})
+
+			if result.RevocationStatus != tc.expectedResult {
+				t.Errorf("失効状態が正しくありません。期待: %s, 実際: %s", tc.expectedResult, result.RevocationStatus)
+			}
+			if result.Revoked != tc.expectRevoked {
+				t.Errorf("失効フラグが正しくありません。期待: %v, 実際: %v", tc.expectRevoked, result.Revoked)
+			}
+			if tc.expectRevoked && result.Status != "CRITICAL" {
+				t.Errorf("ステータスが正しくありません。期待: CRITICAL, 実際: %s", result.Status)
+			}
+		})
+	}
+}
+
+// TestCheckOCSPRevocationNoResponder OCSPレスポンダー未指定時のテスト
+func TestCheckOCSPRevocationNoResponder(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+	leaf := newTestCert(t, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "no-ocsp.example.com"},
+	}, &ca)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	info := CertInfo{Status: "OK"}
+	checkOCSPRevocation(context.Background(), &Config{}, &info, []*x509.Certificate{leaf.cert, ca.cert})
+
+	// レスポンダーがない場合はステータスを変更しない
+	if info.Status != "OK" {
+		t.Errorf("ステータスが変更されました。期待: OK, 実際: %s", info.Status)
+	}
+	if info.RevocationStatus != "" {
+		t.Errorf("失効状態が設定されました: %s", info.RevocationStatus)
+	}
+}
+
+// TestCheckCertificateCRL CRLによる失効確認のテスト
+func TestCheckCertificateCRL(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CRL CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil)
+
+	revokedSerial := big.NewInt(1001)
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(24 * time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: revokedSerial, RevocationTime: time.Now().Add(-time.Hour)},
+		},
+	}, ca.cert, ca.key)
+	if err != nil {
+		t.Fatalf("CRLの生成に失敗: %v", err)
+	}
+
+	var downloads int32
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		w.Header().Set("Content-Type", "application/pkix-crl")
+		w.Write(crlDER)
+	}))
+	defer crlServer.Close()
+
+	// テストごとにキャッシュを初期化
+	crls = newCRLCache()
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	testCases := []struct {
+		name          string
+		serial        *big.Int
+		expectRevoked bool
+	}{
+		{name: "失効した証明書", serial: revokedSerial, expectRevoked: true},
+		{name: "有効な証明書", serial: big.NewInt(1002), expectRevoked: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			leaf := newTestCert(t, &x509.Certificate{
+				SerialNumber:          tc.serial,
+				Subject:               pkix.Name{CommonName: "crl.example.com"},
+				DNSNames:              []string{"crl.example.com"},
+				CRLDistributionPoints: []string{crlServer.URL},
+			}, &ca)
+			port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{leaf.tlsCertificate(ca)}})
+
+			config := &Config{}
+			config.Alert.WarningDays = 30
+			config.Alert.CriticalDays = 7
+			config.Alert.CheckCRL = true
+
+			result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "CRL"})
+
+			if result.Revoked != tc.expectRevoked {
+				t.Errorf("失効フラグが正しくありません。期待: %v, 実際: %v", tc.expectRevoked, result.Revoked)
+			}
+			if tc.expectRevoked && result.Status != "CRITICAL" {
+				t.Errorf("ステータスが正しくありません。期待: CRITICAL, 実際: %s", result.Status)
+			}
+			if !tc.expectRevoked && result.RevocationStatus != "GOOD" {
+				t.Errorf("失効状態が正しくありません。期待: GOOD, 実際: %s", result.RevocationStatus)
+			}
+		})
+	}
+
+	// 同じ配布ポイントのCRLは1回だけダウンロードされる
+	if n := atomic.LoadInt32(&downloads); n != 1 {
+		t.Errorf("CRLのダウンロード回数が正しくありません。期待: 1, 実際: %d", n)
+	}
+}
+
+// TestCheckCertificateOCSPUnknownCRLFallback OCSPレスポンダーがUNKNOWNを返した場合にCRLで確認するテスト
+func TestCheckCertificateOCSPUnknownCRLFallback(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test Fallback CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil)
+
+	status := ocsp.Unknown
+	responder := newOCSPResponder(t, &ca, &status)
+
+	revokedSerial := big.NewInt(2001)
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(24 * time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: revokedSerial, RevocationTime: time.Now().Add(-time.Hour)},
+		},
+	}, ca.cert, ca.key)
+	if err != nil {
+		t.Fatalf("CRLの生成に失敗: %v", err)
+	}
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(crlDER)
+	}))
+	defer crlServer.Close()
+
+	// テストごとにキャッシュを初期化
+	crls = newCRLCache()
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	leaf := newTestCert(t, &x509.Certificate{
+		SerialNumber:          revokedSerial,
+		Subject:               pkix.Name{CommonName: "fallback.example.com"},
+		DNSNames:              []string{"fallback.example.com"},
+		OCSPServer:            []string{responder.URL},
+		CRLDistributionPoints: []string{crlServer.URL},
+	}, &ca)
+	port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{leaf.tlsCertificate(ca)}})
+
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+	config.Alert.CheckOCSP = true
+	config.Alert.CheckCRL = true
+
+	result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "Fallback"})
+	if result.RevocationStatus != "REVOKED" || !result.Revoked {
+		t.Errorf("CRLで失効が検出されていません。失効状態: %s, 失効フラグ: %v", result.RevocationStatus, result.Revoked)
+	}
+	if result.Status != "CRITICAL" {
+		t.Errorf("ステータスが正しくありません。期待: CRITICAL, 実際: %s", result.Status)
+	}
+}
+
+// TestRevocationClientProxy alert.socks_proxyやproxy_urlを設定した場合にCRLをプロキシ経由で取得するテスト
+func TestRevocationClientProxy(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test Proxy CRL CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil)
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(24 * time.Hour),
+	}, ca.cert, ca.key)
+	if err != nil {
+		t.Fatalf("CRLの生成に失敗: %v", err)
+	}
+
+	// HTTPプロキシにはリクエストが絶対URLで送られ、SOCKS5プロキシからは通常のリクエストとして転送される
+	var requests []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Host)
+		mu.Unlock()
+		w.Write(crlDER)
+	}))
+	defer server.Close()
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	targets := make(chan string, 1)
+	testCases := []struct {
+		name     string
+		settings string
+	}{
+		{name: "socks_proxy", settings: "alert:\n  socks_proxy: " + startSOCKS5Server(t, port, targets) + "\n"},
+		{name: "proxy_url", settings: "proxy_url: " + server.URL + "\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tc.settings), 0600); err != nil {
+				t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+			}
+			config, err := LoadConfig(configPath)
+			if err != nil {
+				t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+			}
+
+			// .invalidは直接は名前解決できないため、プロキシ経由で接続した場合だけ取得できる
+			if _, err := newCRLCache().get(context.Background(), revocationHTTPClient(config), "http://crl.internal.invalid/ca.crl", ca.cert); err != nil {
+				t.Fatalf("プロキシ経由でCRLを取得できませんでした: %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(requests) == 0 || requests[len(requests)-1] != "crl.internal.invalid" {
+				t.Errorf("リクエストの宛先が正しくありません: %v", requests)
+			}
+		})
+	}
+
+	select {
+	case target := <-targets:
+		if target != "crl.internal.invalid:80" {
+			t.Errorf("プロキシへの接続先が正しくありません。期待: crl.internal.invalid:80, 実際: %s", target)
+		}
+	default:
+		t.Error("SOCKS5プロキシを経由していません")
+	}
+	if revocationHTTPClient(&Config{}) != revocationClient {
+		t.Error("プロキシ未指定時に共通のクライアントが返されませんでした")
+	}
+}
+
+// TestCRLCacheExpiry CRLのキャッシュ期間がcrlCacheTTLを超えず、期限切れのエントリが削除されることのテスト
+func TestCRLCacheExpiry(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CRL Cache CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil)
+
+	// 次回更新日時が1週間後のCRL
+	crlDER, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(1),
+		ThisUpdate: time.Now().Add(-time.Hour),
+		NextUpdate: time.Now().Add(7 * 24 * time.Hour),
+	}, ca.cert, ca.key)
+	if err != nil {
+		t.Fatalf("CRLの生成に失敗: %v", err)
+	}
+
+	var downloads int32
+	crlServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		w.Write(crlDER)
+	}))
+	defer crlServer.Close()
+
+	cache := newCRLCache()
+	if _, err := cache.get(context.Background(), revocationClient, crlServer.URL, ca.cert); err != nil {
+		t.Fatalf("CRLの取得に失敗: %v", err)
+	}
+	entry := cache.entries[crlServer.URL]
+	if limit := time.Now().Add(crlCacheTTL); entry.expiresAt.After(limit) {
+		t.Errorf("キャッシュの期限がcrlCacheTTLを超えています。期待: %v 以前, 実際: %v", limit, entry.expiresAt)
+	}
+
+	// 期限が切れたCRLは再取得する
+	entry.expiresAt = time.Now().Add(-time.Second)
+	if _, err := cache.get(context.Background(), revocationClient, crlServer.URL, ca.cert); err != nil {
+		t.Fatalf("CRLの再取得に失敗: %v", err)
+	}
+	if n := atomic.LoadInt32(&downloads); n != 2 {
+		t.Errorf("CRLのダウンロード回数が正しくありません。期待: 2, 実際: %d", n)
+	}
+
+	// 他のURLの期限切れのエントリと取得に失敗したエントリは削除される
+	cache.entries["http://expired.example.com/ca.crl"] = &crlCacheEntry{crl: &x509.RevocationList{}, expiresAt: time.Now().Add(-time.Second)}
+	cache.entries["http://failed.example.com/ca.crl"] = &crlCacheEntry{}
+	if _, err := cache.get(context.Background(), revocationClient, crlServer.URL, ca.cert); err != nil {
+		t.Fatalf("CRLの取得に失敗: %v", err)
+	}
+	if len(cache.entries) != 1 {
+		t.Errorf("キャッシュのエントリ数が正しくありません。期待: 1, 実際: %d", len(cache.entries))
+	}
+}
+
+// TestCheckCertificateMustStaple Must-Stapleの証明書でOCSPレスポンスがステープルされていない場合のテスト
+func TestCheckCertificateMustStaple(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	ca := newTestCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test Must-Staple CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+
+	tlsFeature, err := asn1.Marshal([]int{tlsFeatureStatusRequest})
+	if err != nil {
+		t.Fatalf("TLS Feature拡張の作成に失敗: %v", err)
+	}
+	mustStaple := newTestCert(t, &x509.Certificate{
+		Subject:         pkix.Name{CommonName: "staple.example.com"},
+		DNSNames:        []string{"staple.example.com"},
+		ExtraExtensions: []pkix.Extension{{Id: oidTLSFeature, Value: tlsFeature}},
+	}, &ca)
+	plain := newTestCert(t, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "staple.example.com"},
+		DNSNames: []string{"staple.example.com"},
+	}, &ca)
+
+	// サーバーがステープルするOCSPレスポンス
+	staple, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: mustStaple.cert.SerialNumber,
+		ThisUpdate:   time.Now().Add(-time.Hour),
+		NextUpdate:   time.Now().Add(time.Hour),
+	}, ca.key)
+	if err != nil {
+		t.Fatalf("OCSPレスポンスの作成に失敗: %v", err)
+	}
+	stapled := mustStaple.tlsCertificate(ca)
+	stapled.OCSPStaple = staple
+
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+	config.rootCAs = x509.NewCertPool()
+	config.rootCAs.AddCert(ca.cert)
+
+	testCases := []struct {
+		name       string
+		cert       tls.Certificate
+		mustStaple bool
+		stapled    bool
+		status     string
+	}{
+		{name: "Must-Stapleでステープルなし", cert: mustStaple.tlsCertificate(ca), mustStaple: true, stapled: false, status: "WARNING"},
+		{name: "Must-Stapleでステープルあり", cert: stapled, mustStaple: true, stapled: true, status: "OK"},
+		{name: "Must-Stapleなし", cert: plain.tlsCertificate(ca), mustStaple: false, stapled: false, status: "OK"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			port := startTLSServer(t, &tls.Config{Certificates: []tls.Certificate{tc.cert}})
+
+			result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, ServerName: "staple.example.com", Name: "Must-Staple"})
+			if result.MustStaple != tc.mustStaple {
+				t.Errorf("MustStaple 期待: %v, 実際: %v", tc.mustStaple, result.MustStaple)
+			}
+			if result.OCSPStapled != tc.stapled {
+				t.Errorf("OCSPStapled 期待: %v, 実際: %v", tc.stapled, result.OCSPStapled)
+			}
+			if result.Status != tc.status {
+				t.Errorf("ステータス 期待: %s, 実際: %s（%s）", tc.status, result.Status, result.ErrorMessage)
+			}
+			if tc.status == "WARNING" && !strings.Contains(result.ErrorMessage, "Must-Staple") {
+				t.Errorf("警告メッセージが正しくありません: %s", result.ErrorMessage)
+			}
+		})
+	}
+}
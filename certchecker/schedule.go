@@ -0,0 +1,39 @@
+package certchecker
+
+import (
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// scheduleParser 標準的な5フィールドのcron式（分 時 日 月 曜日）を解析する
+// 先頭に秒のフィールドを付けた6フィールドの式や、@daily などの記述子も受け付ける
+var scheduleParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// ParseSchedule cron式を解析する
+func ParseSchedule(expr string) (cron.Schedule, error) {
+	return scheduleParser.Parse(expr)
+}
+
+// RunScheduled コンテキストがキャンセルされるまで、cron式のスケジュールに従ってrunを実行する
+// 実行中にキャンセルされた場合は、その回の実行が終わるのを待ってから戻る
+func RunScheduled(ctx context.Context, schedule cron.Schedule, run func()) {
+	LogInfof("スケジュールモードで起動しました（次回: %s）", schedule.Next(time.Now()).Format("2006-01-02 15:04:05 MST"))
+	for cycle := 1; ; cycle++ {
+		next := schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			LogInfof("停止要求を受け付けたためスケジュールモードを終了します")
+			return
+		case <-timer.C:
+		}
+
+		runCycle(cycle, run)
+		LogInfof("次回のチェック: %s", schedule.Next(time.Now()).Format("2006-01-02 15:04:05 MST"))
+	}
+}
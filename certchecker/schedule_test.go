@@ -0,0 +1,82 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+)
+
+// TestParseSchedule cron式の解析のテスト
+func TestParseSchedule(t *testing.T) {
+	testCases := []struct {
+		expr  string
+		valid bool
+	}{
+		{"0 9 * * *", true},
+		{"*/15 * * * *", true},
+		{"0 0 9 * * 1-5", true},
+		{"@daily", true},
+		{"0 9 * *", false},
+		{"61 * * * *", false},
+		{"every day", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			_, err := ParseSchedule(tc.expr)
+			if (err == nil) != tc.valid {
+				t.Errorf("解析結果が正しくありません。期待: %v, 実際: %v", tc.valid, err)
+			}
+		})
+	}
+
+	// 毎日9時の式は、8時の次に同日の9時を返す
+	schedule, err := ParseSchedule("0 9 * * *")
+	if err != nil {
+		t.Fatalf("cron式の解析に失敗: %v", err)
+	}
+	now := time.Date(2025, 6, 1, 8, 30, 0, 0, time.Local)
+	expected := time.Date(2025, 6, 1, 9, 0, 0, 0, time.Local)
+	if next := schedule.Next(now); !next.Equal(expected) {
+		t.Errorf("次回の実行日時が正しくありません。期待: %v, 実際: %v", expected, next)
+	}
+}
+
+// TestRunScheduled スケジュールに従って実行され、キャンセルで終了することのテスト
+func TestRunScheduled(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	// 毎秒実行されるスケジュール
+	schedule, err := ParseSchedule("* * * * * *")
+	if err != nil {
+		t.Fatalf("cron式の解析に失敗: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		RunScheduled(ctx, schedule, func() {
+			runs <- struct{}{}
+			cancel()
+		})
+	}()
+
+	select {
+	case <-runs:
+	case <-time.After(3 * time.Second):
+		t.Fatal("スケジュールされた時刻に実行されませんでした")
+	}
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("キャンセル後にスケジュールモードが終了しませんでした")
+	}
+}
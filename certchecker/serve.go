@@ -0,0 +1,94 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultServeInterval メトリクスを公開する場合の再チェック間隔のデフォルト値
+const DefaultServeInterval = 1 * time.Hour
+
+// metricsExporter 直近のチェック結果を保持し、Prometheus形式で公開するハンドラー
+type metricsExporter struct {
+	mu      sync.RWMutex
+	results []CertInfo
+	checked bool
+}
+
+// update チェック結果を差し替える
+func (e *metricsExporter) update(results []CertInfo) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.results = results
+	e.checked = true
+}
+
+// ServeHTTP 直近のチェック結果をメトリクスとして返す
+func (e *metricsExporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	results, checked := e.results, e.checked
+	e.mu.RUnlock()
+
+	// 初回のチェックが終わる前に空のメトリクスを返すと、すべての証明書が消えたように見えるため503を返す
+	if !checked {
+		http.Error(w, "初回のチェックが完了していません", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(w, GeneratePrometheusReport(results))
+}
+
+// ServeMetrics /metricsでメトリクスを公開し、一定間隔でチェックを繰り返す
+// ctxがキャンセルされるとチェックを中断してサーバーを停止し、それまでは戻らない
+func ServeMetrics(ctx context.Context, config *Config, addr string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultServeInterval
+	}
+
+	exporter := &metricsExporter{}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			results := CheckAllSites(ctx, config)
+			// 中断したチェックの結果で直近の結果を置き換えない
+			if ctx.Err() != nil {
+				return
+			}
+			exporter.update(results)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: defaultTimeout,
+	}
+
+	LogInfof("メトリクスを公開します: http://%s/metrics (チェック間隔: %v)", addr, interval)
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- server.ListenAndServe() }()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	LogInfof("停止要求を受け付けたためメトリクスの公開を終了します")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
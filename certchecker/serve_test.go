@@ -0,0 +1,101 @@
+package certchecker
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMetricsExporter /metricsハンドラーのテスト
+func TestMetricsExporter(t *testing.T) {
+	exporter := &metricsExporter{}
+	server := httptest.NewServer(exporter)
+	defer server.Close()
+
+	// 初回のチェックが終わるまでは503
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("リクエストに失敗: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("ステータスコードが正しくありません。期待: %d, 実際: %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+
+	exporter.update([]CertInfo{
+		{SiteName: "Test Site", URL: "example.com", Port: 443, DaysRemaining: 45, Status: "OK"},
+	})
+
+	resp, err = http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("リクエストに失敗: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("レスポンスの読み込みに失敗: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("ステータスコードが正しくありません。期待: %d, 実際: %d", http.StatusOK, resp.StatusCode)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Errorf("Content-Typeが正しくありません: %s", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), `ssl_cert_expiry_days{site="Test Site",url="example.com:443"} 45`) {
+		t.Errorf("メトリクスに有効期限が含まれていません\n%s", body)
+	}
+}
+
+// TestServeMetricsShutdown ctxのキャンセルでメトリクスの公開を終了するテスト
+func TestServeMetricsShutdown(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ポートの確保に失敗: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- ServeMetrics(ctx, &Config{}, addr, time.Hour) }()
+
+	// 初回のチェックが終わり、メトリクスを返すまで待つ
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("メトリクスが公開されませんでした: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("停止時にエラーが返されました: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("キャンセル後もServeMetricsが終了しません")
+	}
+	if _, err := http.Get("http://" + addr + "/metrics"); err == nil {
+		t.Error("停止後もメトリクスが公開されています")
+	}
+}
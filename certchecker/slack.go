@@ -0,0 +1,134 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// slackPlaceholderWebhookURL 設定例に記載しているWebhook URL（未設定として扱う）
+const slackPlaceholderWebhookURL = "https://hooks.slack.com/services/YOUR/WEBHOOK/URL"
+
+// slackColors ステータスに応じたアタッチメントの色
+var slackColors = map[string]string{
+	"OK":       "#2eb886", // 緑
+	"WARNING":  "#ffa500", // オレンジ
+	"CRITICAL": "#ff0000", // 赤
+	"ERROR":    "#8b0000", // 暗い赤
+}
+
+// slackText Block Kitのテキストオブジェクト
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// slackBlock Block Kitのブロック
+type slackBlock struct {
+	Type   string      `json:"type"`
+	Text   *slackText  `json:"text,omitempty"`
+	Fields []slackText `json:"fields,omitempty"`
+}
+
+// slackAttachment ステータスの色を付けるためのアタッチメント
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// slackPayload Incoming Webhookに送信するメッセージ
+type slackPayload struct {
+	Channel     string            `json:"channel,omitempty"`
+	Username    string            `json:"username"`
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+// SendSlackNotification Slackに通知を送信
+func SendSlackNotification(ctx context.Context, config *Config, results []CertInfo) error {
+	if !config.Slack.Enabled {
+		LogDebugf("Slack通知は無効です")
+		return nil
+	}
+
+	webhookURL := config.Slack.WebhookURL
+	if webhookURL == "" || webhookURL == slackPlaceholderWebhookURL {
+		LogWarnf("Slack Webhook URLが設定されていません")
+		return nil
+	}
+
+	// 通知対象の結果をフィルタリング
+	filteredResults := filterByStatus(results, config.Slack.NotifyOn)
+
+	if len(filteredResults) == 0 {
+		LogDebugf("Slack通知対象の結果がありません")
+		return nil
+	}
+
+	attachments := []slackAttachment{}
+	for _, cert := range filteredResults {
+		color, ok := slackColors[cert.Status]
+		if !ok {
+			color = "#808080" // グレー
+		}
+
+		fields := []slackText{
+			{Type: "mrkdwn", Text: fmt.Sprintf("*URL*\n%s", displayAddress(cert.URL, cert.Port))},
+			{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", config.message("status"), cert.Status)},
+		}
+		if cert.Status != "ERROR" {
+			fields = append(fields,
+				slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", config.message("days_remaining"), fmt.Sprintf(config.message("days"), cert.DaysRemaining))},
+				slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", config.message("not_after"), config.formatTime(cert.NotAfter))},
+				slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", config.message("issuer"), cert.Issuer)},
+			)
+		}
+
+		blocks := []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*", notificationTitle(config, cert))}},
+			{Type: "section", Fields: fields},
+		}
+		if cert.ErrorMessage != "" {
+			label := config.message("warning")
+			if cert.Status == "ERROR" {
+				label = config.message("error")
+			}
+			blocks = append(blocks, slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", label, cert.ErrorMessage)}})
+		}
+
+		attachments = append(attachments, slackAttachment{Color: color, Blocks: blocks})
+	}
+
+	payload := slackPayload{
+		Channel:     config.Slack.Channel,
+		Username:    config.message("app_name"),
+		Text:        fmt.Sprintf(config.message("notification_title"), config.formatTime(time.Now())),
+		Attachments: attachments,
+	}
+	// 放置されたCRITICALに気付けるよう、エスカレーション中はチャンネル全体にメンションする
+	if escalated(filteredResults) {
+		payload.Text = "<!channel> " + payload.Text
+	}
+
+	// JSONに変換
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("JSONのマーシャルに失敗: %v", err)
+	}
+
+	// Webhookに送信
+	resp, err := postJSON(ctx, notifyHTTPClient(config, config.Slack.Timeout), webhookURL, jsonData)
+	if err != nil {
+		return fmt.Errorf("Slack通知の送信に失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slackがエラーを返しました: %d", resp.StatusCode)
+	}
+
+	LogInfof("Slack通知を送信しました")
+	return nil
+}
@@ -0,0 +1,128 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSendSlackNotificationDisabled Slack通知無効時のテスト
+func TestSendSlackNotificationDisabled(t *testing.T) {
+	config := &Config{}
+	config.Slack.Enabled = false
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+	}
+
+	if err := SendSlackNotification(context.Background(), config, results); err != nil {
+		t.Errorf("Slack通知無効時にエラーが発生しました: %v", err)
+	}
+}
+
+// TestSendSlackNotificationNoWebhook Webhook URL未設定時のテスト
+func TestSendSlackNotificationNoWebhook(t *testing.T) {
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+	}
+
+	for _, webhookURL := range []string{"", slackPlaceholderWebhookURL} {
+		config := &Config{}
+		config.Slack.Enabled = true
+		config.Slack.WebhookURL = webhookURL
+
+		if err := SendSlackNotification(context.Background(), config, results); err != nil {
+			t.Errorf("Webhook URL未設定時にエラーが発生しました (%q): %v", webhookURL, err)
+		}
+	}
+}
+
+// TestSendSlackNotificationFiltering 通知フィルタリングとメッセージ内容のテスト
+func TestSendSlackNotificationFiltering(t *testing.T) {
+	var received []slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload slackPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("ペイロードの解析に失敗: %v", err)
+		}
+		received = append(received, payload)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	config := &Config{}
+	config.Slack.Enabled = true
+	config.Slack.WebhookURL = server.URL
+	config.Slack.Channel = "#alerts"
+	config.Slack.NotifyOn = []string{"CRITICAL", "ERROR"}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	// 通知対象がない場合は送信しない
+	err := SendSlackNotification(context.Background(), config, []CertInfo{
+		{SiteName: "OK Site", URL: "ok.com", Port: 443, Status: "OK", DaysRemaining: 90},
+		{SiteName: "Warning Site", URL: "warning.com", Port: 443, Status: "WARNING", DaysRemaining: 20},
+	})
+	if err != nil {
+		t.Errorf("通知対象なし時にエラーが発生しました: %v", err)
+	}
+	if len(received) != 0 {
+		t.Fatalf("通知対象がないのに送信されました: %d件", len(received))
+	}
+
+	err = SendSlackNotification(context.Background(), config, []CertInfo{
+		{SiteName: "OK Site", URL: "ok.com", Port: 443, Status: "OK", DaysRemaining: 90},
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+		{SiteName: "Error Site", URL: "error.com", Port: 443, Status: "ERROR", ErrorMessage: "Connection failed"},
+	})
+	if err != nil {
+		t.Fatalf("Slack通知でエラーが発生しました: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("送信回数が正しくありません。期待: 1, 実際: %d", len(received))
+	}
+
+	payload := received[0]
+	if payload.Channel != "#alerts" {
+		t.Errorf("チャンネルが正しくありません。期待: #alerts, 実際: %s", payload.Channel)
+	}
+	if len(payload.Attachments) != 2 {
+		t.Fatalf("アタッチメントの数が正しくありません。期待: 2, 実際: %d", len(payload.Attachments))
+	}
+	if payload.Attachments[0].Color != slackColors["CRITICAL"] || payload.Attachments[1].Color != slackColors["ERROR"] {
+		t.Errorf("アタッチメントの色が正しくありません: %s, %s", payload.Attachments[0].Color, payload.Attachments[1].Color)
+	}
+	if text := payload.Attachments[0].Blocks[0].Text.Text; text != "*🔒 Critical Site*" {
+		t.Errorf("サイト名のブロックが正しくありません: %s", text)
+	}
+	if strings.HasPrefix(payload.Text, "<!channel>") {
+		t.Errorf("エスカレーションしていないのにチャンネル全体にメンションしました: %s", payload.Text)
+	}
+
+	// エスカレーション中のサイトがある場合はチャンネル全体にメンションする
+	err = SendSlackNotification(context.Background(), config, []CertInfo{
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3, CriticalRuns: 3, EscalationLevel: 1},
+	})
+	if err != nil {
+		t.Fatalf("Slack通知でエラーが発生しました: %v", err)
+	}
+	payload = received[len(received)-1]
+	if !strings.HasPrefix(payload.Text, "<!channel> ") {
+		t.Errorf("エスカレーション時にチャンネル全体へのメンションがありません: %s", payload.Text)
+	}
+	if text := payload.Attachments[0].Blocks[0].Text.Text; text != "*🚨 Critical Site（エスカレーション レベル1: CRITICALが3回連続）*" {
+		t.Errorf("サイト名のブロックが正しくありません: %s", text)
+	}
+}
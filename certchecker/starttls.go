@@ -0,0 +1,284 @@
+package certchecker
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// contextDialer TCP接続を確立するダイアラー（直接接続するnet.Dialerか、SOCKS5プロキシ経由のダイアラー）
+type contextDialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// dialTLS TLS接続を確立する
+// starttlsが指定されている場合は平文で接続し、プロトコルごとのSTARTTLS手順を経てからTLSハンドシェイクを行う
+// timeoutはTCP接続と、接続後のSTARTTLSのやり取りとハンドシェイクのそれぞれに適用する
+// ctxがキャンセルされた場合は、接続やハンドシェイクの途中でも中断する
+func dialTLS(ctx context.Context, dialer contextDialer, timeout time.Duration, address string, conf *tls.Config, starttls string) (*tls.Conn, error) {
+	dialCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		dialCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	// STARTTLSのやり取りとハンドシェイク全体にタイムアウトを適用する
+	if timeout > 0 {
+		conn.SetDeadline(time.Now().Add(timeout))
+	}
+	// ctxがキャンセルされた場合は期限を過去にして、応答待ちの読み書きを中断させる
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if starttls != "" {
+		if err := negotiateStartTLS(conn, starttls); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	tlsConn := tls.Client(conn, conf)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	stop()
+	conn.SetDeadline(time.Time{})
+
+	return tlsConn, nil
+}
+
+// startTLSProtocols サイトのstarttlsで指定できるプロトコル
+var startTLSProtocols = []string{"smtp", "imap", "pop3", "postgres", "mysql"}
+
+// negotiateStartTLS 平文の接続上でTLSへの切り替えを要求する
+func negotiateStartTLS(conn net.Conn, protocol string) error {
+	switch protocol {
+	case "smtp":
+		return startTLSSMTP(conn)
+	case "imap":
+		return startTLSIMAP(conn)
+	case "pop3":
+		return startTLSPOP3(conn)
+	case "postgres":
+		return startTLSPostgres(conn)
+	case "mysql":
+		return startTLSMySQL(conn)
+	default:
+		return fmt.Errorf("未対応のSTARTTLSプロトコルです: %s", protocol)
+	}
+}
+
+// startTLSSMTP SMTPサーバーにEHLOとSTARTTLSを送信し、TLSへの切り替えを要求する
+func startTLSSMTP(conn net.Conn) error {
+	text := textproto.NewConn(conn)
+
+	if _, _, err := text.ReadResponse(220); err != nil {
+		return fmt.Errorf("SMTPの応答が不正です: %v", err)
+	}
+
+	if err := text.PrintfLine("EHLO cert-checker"); err != nil {
+		return err
+	}
+	if _, _, err := text.ReadResponse(250); err != nil {
+		return fmt.Errorf("EHLOに失敗: %v", err)
+	}
+
+	if err := text.PrintfLine("STARTTLS"); err != nil {
+		return err
+	}
+	if _, _, err := text.ReadResponse(220); err != nil {
+		return fmt.Errorf("STARTTLSに失敗: %v", err)
+	}
+
+	return nil
+}
+
+// startTLSIMAP IMAPサーバーにSTARTTLSコマンドを送信し、TLSへの切り替えを要求する
+func startTLSIMAP(conn net.Conn) error {
+	text := textproto.NewConn(conn)
+
+	greeting, err := text.ReadLine()
+	if err != nil {
+		return fmt.Errorf("IMAPの応答の読み込みに失敗: %v", err)
+	}
+	// 接続元によって認証済みとして扱うサーバーは、OKの代わりにPREAUTHを返す（RFC 3501）
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		return fmt.Errorf("IMAPの応答が不正です: %s", greeting)
+	}
+
+	if err := text.PrintfLine("a1 STARTTLS"); err != nil {
+		return err
+	}
+	// タグ付きの応答が返るまで、途中の未タグ応答は読み飛ばす
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return fmt.Errorf("STARTTLSの応答の読み込みに失敗: %v", err)
+		}
+		if !strings.HasPrefix(line, "a1 ") {
+			continue
+		}
+		if !strings.HasPrefix(line, "a1 OK") {
+			return fmt.Errorf("STARTTLSに失敗: %s", line)
+		}
+		return nil
+	}
+}
+
+// startTLSPOP3 POP3サーバーにSTLSコマンドを送信し、TLSへの切り替えを要求する
+func startTLSPOP3(conn net.Conn) error {
+	text := textproto.NewConn(conn)
+
+	greeting, err := text.ReadLine()
+	if err != nil {
+		return fmt.Errorf("POP3の応答の読み込みに失敗: %v", err)
+	}
+	if !strings.HasPrefix(greeting, "+OK") {
+		return fmt.Errorf("POP3の応答が不正です: %s", greeting)
+	}
+
+	if err := text.PrintfLine("STLS"); err != nil {
+		return err
+	}
+	line, err := text.ReadLine()
+	if err != nil {
+		return fmt.Errorf("STLSの応答の読み込みに失敗: %v", err)
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("STLSに失敗: %s", line)
+	}
+
+	return nil
+}
+
+// postgresSSLRequestCode PostgreSQLのSSLRequestメッセージで送信するリクエストコード（1234 << 16 | 5679）
+const postgresSSLRequestCode = 80877103
+
+// startTLSPostgres PostgreSQLサーバーにSSLRequestを送信し、TLSへの切り替えを要求する
+//
+// 送信するSSLRequestは8バイトで、いずれもビッグエンディアン:
+//
+//	00 00 00 08  メッセージ長（長さ自身を含む）
+//	04 d2 16 2f  リクエストコード 80877103
+//
+// サーバーはTLSに対応していれば 'S'、対応していなければ 'N' の1バイトを返す
+func startTLSPostgres(conn net.Conn) error {
+	var req [8]byte
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(req[:]); err != nil {
+		return err
+	}
+
+	var resp [1]byte
+	if _, err := io.ReadFull(conn, resp[:]); err != nil {
+		return fmt.Errorf("SSLRequestの応答の読み込みに失敗: %v", err)
+	}
+	switch resp[0] {
+	case 'S':
+		return nil
+	case 'N':
+		return errors.New("サーバーがTLSに対応していません")
+	default:
+		return fmt.Errorf("SSLRequestの応答が不正です: %q", resp[0])
+	}
+}
+
+// MySQLのケイパビリティフラグ
+const (
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientSSL              = 0x00000800
+	mysqlClientSecureConnection = 0x00008000
+)
+
+// startTLSMySQL MySQLサーバーの初期ハンドシェイクを受信し、SSLRequestを送信してTLSへの切り替えを要求する
+//
+// MySQLのパケットは 3バイトのペイロード長（リトルエンディアン）+ 1バイトのシーケンス番号 + ペイロード で構成される。
+// サーバーから受信する初期ハンドシェイク（HandshakeV10）のペイロード:
+//
+//	1バイト          プロトコルバージョン（10。0xffの場合はエラーパケット）
+//	NUL終端文字列    サーバーバージョン
+//	4バイト          接続ID
+//	8バイト          認証データ（前半）
+//	1バイト          フィラー
+//	2バイト          ケイパビリティフラグ（下位16ビット）。CLIENT_SSL (0x0800) が立っていればTLSに対応
+//
+// 送信するSSLRequestはシーケンス番号1、32バイトのペイロードで、いずれもリトルエンディアン:
+//
+//	4バイト  ケイパビリティフラグ（CLIENT_PROTOCOL_41 | CLIENT_SSL | CLIENT_SECURE_CONNECTION）
+//	4バイト  最大パケットサイズ
+//	1バイト  文字セット（0x21 = utf8_general_ci）
+//	23バイト 予約領域（すべて0）
+//
+// 送信後は応答を待たずにTLSハンドシェイクを開始する
+func startTLSMySQL(conn net.Conn) error {
+	var header [4]byte
+	if _, err := io.ReadFull(conn, header[:]); err != nil {
+		return fmt.Errorf("MySQLの初期ハンドシェイクの読み込みに失敗: %v", err)
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return fmt.Errorf("MySQLの初期ハンドシェイクの読み込みに失敗: %v", err)
+	}
+
+	if len(payload) == 0 || payload[0] != 10 {
+		return errors.New("MySQLの初期ハンドシェイクが不正です")
+	}
+	versionEnd := bytes.IndexByte(payload[1:], 0)
+	if versionEnd < 0 {
+		return errors.New("MySQLの初期ハンドシェイクが不正です")
+	}
+	capOffset := 1 + versionEnd + 1 + 4 + 8 + 1
+	if len(payload) < capOffset+2 {
+		return errors.New("MySQLの初期ハンドシェイクが不正です")
+	}
+	capabilities := binary.LittleEndian.Uint16(payload[capOffset : capOffset+2])
+	if capabilities&mysqlClientSSL == 0 {
+		return errors.New("サーバーがTLSに対応していません")
+	}
+
+	req := make([]byte, 4+32)
+	req[0] = 32 // ペイロード長
+	req[3] = 1  // シーケンス番号
+	binary.LittleEndian.PutUint32(req[4:8], mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+	binary.LittleEndian.PutUint32(req[8:12], 1<<24)
+	req[12] = 0x21
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// defaultPort ポートが省略された場合に使用するポート番号
+func defaultPort(starttls string) int {
+	switch starttls {
+	case "smtp":
+		return 25
+	case "imap":
+		return 143
+	case "pop3":
+		return 110
+	case "postgres":
+		return 5432
+	case "mysql":
+		return 3306
+	default:
+		return 443
+	}
+}
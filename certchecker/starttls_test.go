@@ -0,0 +1,318 @@
+package certchecker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// startSMTPServer STARTTLSに対応したモックSMTPサーバーを起動し、ポート番号を返す
+func startSMTPServer(t *testing.T, tlsConfig *tls.Config) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("リスナーの作成に失敗: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				text := textproto.NewConn(c)
+				text.PrintfLine("220 mock.example.com ESMTP")
+				for {
+					line, err := text.ReadLine()
+					if err != nil {
+						return
+					}
+					switch {
+					case strings.HasPrefix(line, "EHLO"):
+						text.PrintfLine("250-mock.example.com")
+						text.PrintfLine("250 STARTTLS")
+					case line == "STARTTLS":
+						text.PrintfLine("220 Ready to start TLS")
+						tls.Server(c, tlsConfig).Handshake()
+						return
+					default:
+						text.PrintfLine("502 Command not implemented")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// TestCheckCertificateStartTLS STARTTLSによる証明書取得のテスト
+func TestCheckCertificateStartTLS(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "mail.example.com"},
+		DNSNames: []string{"mail.example.com"},
+	}, nil)
+	port := startSMTPServer(t, &tls.Config{Certificates: []tls.Certificate{cert.tlsCertificate()}})
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Alert.WarningDays = 30
+	config.Alert.CriticalDays = 7
+
+	result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "SMTP", StartTLS: "smtp"})
+	if result.Status == "ERROR" {
+		t.Fatalf("証明書の取得に失敗しました: %s", result.ErrorMessage)
+	}
+	if result.Subject != "mail.example.com" {
+		t.Errorf("主体者が正しくありません。期待: mail.example.com, 実際: %s", result.Subject)
+	}
+
+	// STARTTLSを指定しない場合は平文の応答をTLSとして解釈できずエラーになる
+	result = CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: "SMTP"})
+	if result.Status != "ERROR" {
+		t.Errorf("ステータスが正しくありません。期待: ERROR, 実際: %s", result.Status)
+	}
+}
+
+// startMailServer 指定したプロトコルでSTARTTLSに対応したモックメールサーバーを起動し、ポート番号を返す
+// imap-preauthは、挨拶でPREAUTHを返すIMAPサーバー
+func startMailServer(t *testing.T, protocol string, tlsConfig *tls.Config) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("リスナーの作成に失敗: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				text := textproto.NewConn(c)
+				switch protocol {
+				case "imap", "imap-preauth":
+					if protocol == "imap-preauth" {
+						text.PrintfLine("* PREAUTH IMAP4rev1 server logged in as admin")
+					} else {
+						text.PrintfLine("* OK IMAP4rev1 ready")
+					}
+					line, err := text.ReadLine()
+					if err != nil || line != "a1 STARTTLS" {
+						text.PrintfLine("a1 BAD unexpected command")
+						return
+					}
+					text.PrintfLine("a1 OK Begin TLS negotiation now")
+				case "pop3":
+					text.PrintfLine("+OK POP3 ready")
+					line, err := text.ReadLine()
+					if err != nil || line != "STLS" {
+						text.PrintfLine("-ERR unexpected command")
+						return
+					}
+					text.PrintfLine("+OK Begin TLS negotiation")
+				}
+				tls.Server(c, tlsConfig).Handshake()
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// TestCheckCertificateStartTLSMail IMAP/POP3のSTARTTLSによる証明書取得のテスト
+func TestCheckCertificateStartTLSMail(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "mail.example.com"},
+		DNSNames: []string{"mail.example.com"},
+	}, nil)
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert.tlsCertificate()}}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	testCases := []struct {
+		server   string
+		starttls string
+	}{
+		{server: "imap", starttls: "imap"},
+		{server: "imap-preauth", starttls: "imap"},
+		{server: "pop3", starttls: "pop3"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.server, func(t *testing.T) {
+			port := startMailServer(t, tc.server, tlsConfig)
+
+			config := &Config{}
+			config.Alert.WarningDays = 30
+			config.Alert.CriticalDays = 7
+
+			result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: tc.server, StartTLS: tc.starttls})
+			if result.Status == "ERROR" {
+				t.Fatalf("証明書の取得に失敗しました: %s", result.ErrorMessage)
+			}
+			if result.Subject != "mail.example.com" {
+				t.Errorf("主体者が正しくありません。期待: mail.example.com, 実際: %s", result.Subject)
+			}
+		})
+	}
+}
+
+// startDatabaseServer 指定したプロトコルでTLSへの切り替えに対応したモックデータベースサーバーを起動し、ポート番号を返す
+func startDatabaseServer(t *testing.T, protocol string, tlsConfig *tls.Config) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("リスナーの作成に失敗: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(c net.Conn) {
+				defer c.Close()
+				switch protocol {
+				case "postgres":
+					var req [8]byte
+					if _, err := io.ReadFull(c, req[:]); err != nil {
+						return
+					}
+					if binary.BigEndian.Uint32(req[4:8]) != postgresSSLRequestCode {
+						c.Write([]byte("N"))
+						return
+					}
+					c.Write([]byte("S"))
+				case "mysql":
+					// HandshakeV10: プロトコルバージョン、サーバーバージョン、接続ID、認証データ、フィラー、ケイパビリティフラグ
+					payload := []byte{10}
+					payload = append(payload, "8.0.0-mock\x00"...)
+					payload = append(payload, 1, 0, 0, 0)
+					payload = append(payload, make([]byte, 8)...)
+					payload = append(payload, 0)
+					payload = binary.LittleEndian.AppendUint16(payload, mysqlClientProtocol41|mysqlClientSSL|mysqlClientSecureConnection)
+					c.Write(append([]byte{byte(len(payload)), 0, 0, 0}, payload...))
+
+					var req [4 + 32]byte
+					if _, err := io.ReadFull(c, req[:]); err != nil {
+						return
+					}
+					if binary.LittleEndian.Uint32(req[4:8])&mysqlClientSSL == 0 {
+						return
+					}
+				}
+				tls.Server(c, tlsConfig).Handshake()
+			}(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// TestCheckCertificateStartTLSDatabase PostgreSQL/MySQLのTLS切り替えによる証明書取得のテスト
+func TestCheckCertificateStartTLSDatabase(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject:  pkix.Name{CommonName: "db.example.com"},
+		DNSNames: []string{"db.example.com"},
+	}, nil)
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert.tlsCertificate()}}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	for _, protocol := range []string{"postgres", "mysql"} {
+		t.Run(protocol, func(t *testing.T) {
+			port := startDatabaseServer(t, protocol, tlsConfig)
+
+			config := &Config{}
+			config.Alert.WarningDays = 30
+			config.Alert.CriticalDays = 7
+
+			result := CheckCertificate(context.Background(), config, Site{URL: "127.0.0.1", Port: port, Name: protocol, StartTLS: protocol})
+			if result.Status == "ERROR" {
+				t.Fatalf("証明書の取得に失敗しました: %s", result.ErrorMessage)
+			}
+			if result.Subject != "db.example.com" {
+				t.Errorf("主体者が正しくありません。期待: db.example.com, 実際: %s", result.Subject)
+			}
+		})
+	}
+}
+
+// TestStartTLSPostgresRejected TLSに対応していないPostgreSQLサーバーのテスト
+func TestStartTLSPostgresRejected(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go func() {
+		defer server.Close()
+		var req [8]byte
+		io.ReadFull(server, req[:])
+		server.Write([]byte("N"))
+	}()
+
+	err := startTLSPostgres(client)
+	if err == nil || !strings.Contains(err.Error(), "TLSに対応していません") {
+		t.Errorf("エラーが正しくありません: %v", err)
+	}
+}
+
+// TestDefaultPort STARTTLSプロトコルごとのデフォルトポートのテスト
+func TestDefaultPort(t *testing.T) {
+	testCases := []struct {
+		starttls string
+		expected int
+	}{
+		{starttls: "", expected: 443},
+		{starttls: "smtp", expected: 25},
+		{starttls: "imap", expected: 143},
+		{starttls: "pop3", expected: 110},
+		{starttls: "postgres", expected: 5432},
+		{starttls: "mysql", expected: 3306},
+	}
+
+	for _, tc := range testCases {
+		if port := defaultPort(tc.starttls); port != tc.expected {
+			t.Errorf("%q のデフォルトポートが正しくありません。期待: %d, 実際: %d", tc.starttls, tc.expected, port)
+		}
+	}
+}
+
+// TestCheckCertificateStartTLSUnsupported 未対応のSTARTTLSプロトコルのテスト
+func TestCheckCertificateStartTLSUnsupported(t *testing.T) {
+	cert := newTestCert(t, &x509.Certificate{
+		Subject: pkix.Name{CommonName: "mail.example.com"},
+	}, nil)
+	port := startSMTPServer(t, &tls.Config{Certificates: []tls.Certificate{cert.tlsCertificate()}})
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	result := CheckCertificate(context.Background(), &Config{}, Site{URL: "127.0.0.1", Port: port, Name: "SMTP", StartTLS: "gopher"})
+	if result.Status != "ERROR" {
+		t.Errorf("ステータスが正しくありません。期待: ERROR, 実際: %s", result.Status)
+	}
+	if !strings.Contains(result.ErrorMessage, "未対応のSTARTTLSプロトコル") {
+		t.Errorf("エラーメッセージが正しくありません: %s", result.ErrorMessage)
+	}
+}
@@ -0,0 +1,112 @@
+package certchecker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// siteState 状態ファイルに保存するサイトごとの状態
+type siteState struct {
+	Status     string    `json:"status"`
+	Since      time.Time `json:"since"`                // 現在のステータスになった日時
+	Runs       int       `json:"runs,omitempty"`       // 現在のステータスが連続している実行回数
+	Escalation int       `json:"escalation,omitempty"` // 最後に通知したエスカレーションレベル
+}
+
+// stateFile 状態ファイルの構造
+type stateFile struct {
+	UpdatedAt time.Time            `json:"updated_at"`
+	Sites     map[string]siteState `json:"sites"`
+}
+
+// stateKey 状態ファイルでサイトを識別するキー
+func stateKey(cert CertInfo) string {
+	return cert.SiteName + "@" + displayAddress(cert.URL, cert.Port)
+}
+
+// loadState 状態ファイルを読み込む
+// ファイルが存在しない場合（初回実行）は空の状態を返す
+func loadState(path string) (map[string]siteState, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]siteState{}, nil
+	}
+	if err != nil {
+		return map[string]siteState{}, err
+	}
+
+	var state stateFile
+	if err := json.Unmarshal(data, &state); err != nil {
+		return map[string]siteState{}, fmt.Errorf("状態ファイルの解析に失敗: %v", err)
+	}
+	if state.Sites == nil {
+		state.Sites = map[string]siteState{}
+	}
+	return state.Sites, nil
+}
+
+// saveState 今回のステータスを状態ファイルに書き込む
+// ステータスが変化していないサイトは、そのステータスになった日時を引き継ぐ
+// 静穏時間帯のため通知を見送ったサイトや通知の送信に失敗したサイト（unsent）は前回の状態を引き継ぎ、次回以降の実行で通知する
+func saveState(path string, results []CertInfo, previous map[string]siteState, unsent []CertInfo) error {
+	held := make(map[string]bool, len(unsent))
+	for _, result := range unsent {
+		held[stateKey(result)] = true
+	}
+
+	now := time.Now()
+	state := stateFile{
+		UpdatedAt: now,
+		Sites:     make(map[string]siteState, len(results)),
+	}
+	for _, result := range results {
+		key := stateKey(result)
+		// ミュート中・静穏時間帯・送信に失敗したサイトは通知していないため前回の状態を引き継ぎ、通知できるようになった後に変化があれば通知する
+		if result.Muted || held[key] {
+			if prev, ok := previous[key]; ok {
+				state.Sites[key] = prev
+			}
+			continue
+		}
+		since := now
+		if prev, ok := previous[key]; ok && prev.Status == result.Status {
+			since = prev.Since
+		}
+		state.Sites[key] = siteState{
+			Status:     result.Status,
+			Since:      since,
+			Runs:       consecutiveRuns(result, previous),
+			Escalation: result.EscalationLevel,
+		}
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, string(data))
+}
+
+// changedResults 前回からステータスが変化したサイトの結果を返す
+// 前回の状態がないサイトは変化したものとして扱い、OK以外からOKに戻ったサイトは復旧（Recovered）として返す
+// ステータスが同じでも、エスカレーションレベルが前回の通知より上がったサイトは通知の対象とする
+func changedResults(results []CertInfo, previous map[string]siteState) []CertInfo {
+	changed := []CertInfo{}
+	for _, result := range results {
+		prev, ok := previous[stateKey(result)]
+		if ok && prev.Status == result.Status && result.EscalationLevel <= prev.Escalation {
+			continue
+		}
+		if ok {
+			result.PreviousStatus = prev.Status
+			if result.Status == "OK" {
+				result.Recovered = true
+			}
+		}
+		changed = append(changed, result)
+	}
+	return changed
+}
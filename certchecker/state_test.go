@@ -0,0 +1,113 @@
+package certchecker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestChangedResults 状態の変化による通知対象の判定のテスト
+func TestChangedResults(t *testing.T) {
+	results := []CertInfo{
+		{SiteName: "Site A", URL: "a.example.com", Port: 443, Status: "OK"},
+		{SiteName: "Site B", URL: "b.example.com", Port: 443, Status: "CRITICAL"},
+		{SiteName: "Site C", URL: "c.example.com", Port: 443, Status: "WARNING"},
+	}
+
+	t.Run("初回実行", func(t *testing.T) {
+		changed := changedResults(results, map[string]siteState{})
+		if len(changed) != 3 {
+			t.Fatalf("通知対象の数が正しくありません。期待: 3, 実際: %d", len(changed))
+		}
+		for _, result := range changed {
+			if result.Recovered {
+				t.Errorf("初回実行で復旧として扱われました: %s", result.SiteName)
+			}
+		}
+	})
+
+	t.Run("変化なし", func(t *testing.T) {
+		previous := map[string]siteState{}
+		for _, result := range results {
+			previous[stateKey(result)] = siteState{Status: result.Status}
+		}
+		if changed := changedResults(results, previous); len(changed) != 0 {
+			t.Errorf("変化がないのに通知対象があります: %+v", changed)
+		}
+	})
+
+	t.Run("悪化と復旧", func(t *testing.T) {
+		previous := map[string]siteState{
+			stateKey(results[0]): {Status: "CRITICAL"}, // CRITICAL -> OK（復旧）
+			stateKey(results[1]): {Status: "WARNING"},  // WARNING -> CRITICAL（悪化）
+			stateKey(results[2]): {Status: "WARNING"},  // 変化なし
+		}
+		changed := changedResults(results, previous)
+		if len(changed) != 2 {
+			t.Fatalf("通知対象の数が正しくありません。期待: 2, 実際: %d", len(changed))
+		}
+		if changed[0].SiteName != "Site A" || !changed[0].Recovered {
+			t.Errorf("復旧したサイトが正しくありません: %+v", changed[0])
+		}
+		if changed[1].SiteName != "Site B" || changed[1].Recovered {
+			t.Errorf("悪化したサイトが正しくありません: %+v", changed[1])
+		}
+		if changed[0].PreviousStatus != "CRITICAL" || changed[1].PreviousStatus != "WARNING" {
+			t.Errorf("前回のステータス 期待: CRITICAL, WARNING, 実際: %s, %s", changed[0].PreviousStatus, changed[1].PreviousStatus)
+		}
+
+		// 復旧はnotify_onに含まれていなくても通知する
+		filtered := filterByStatus(changed, []string{"CRITICAL"})
+		if len(filtered) != 2 {
+			t.Errorf("復旧したサイトが通知対象から除外されました: %+v", filtered)
+		}
+	})
+}
+
+// TestSaveAndLoadState 状態ファイルの保存と読み込みのテスト
+func TestSaveAndLoadState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	// ファイルがない場合は空の状態
+	state, err := loadState(path)
+	if err != nil || len(state) != 0 {
+		t.Fatalf("初回の読み込みが正しくありません: %v, %v", state, err)
+	}
+
+	results := []CertInfo{
+		{SiteName: "Site A", URL: "a.example.com", Port: 443, Status: "OK"},
+		{SiteName: "Site B", URL: "b.example.com", Port: 443, Status: "CRITICAL"},
+	}
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	previous := map[string]siteState{
+		stateKey(results[0]): {Status: "OK", Since: since},
+		stateKey(results[1]): {Status: "WARNING", Since: since},
+	}
+	if err := saveState(path, results, previous, nil); err != nil {
+		t.Fatalf("状態ファイルの保存に失敗: %v", err)
+	}
+
+	state, err = loadState(path)
+	if err != nil {
+		t.Fatalf("状態ファイルの読み込みに失敗: %v", err)
+	}
+	if state[stateKey(results[0])].Status != "OK" || state[stateKey(results[1])].Status != "CRITICAL" {
+		t.Errorf("保存されたステータスが正しくありません: %+v", state)
+	}
+	// ステータスが変わらなければ開始日時を引き継ぎ、変わった場合は更新する
+	if !state[stateKey(results[0])].Since.Equal(since) {
+		t.Errorf("変化のないサイトの開始日時が引き継がれていません: %v", state[stateKey(results[0])].Since)
+	}
+	if state[stateKey(results[1])].Since.Equal(since) {
+		t.Error("変化したサイトの開始日時が更新されていません")
+	}
+
+	// 壊れたファイルはエラー
+	if err := os.WriteFile(path, []byte("{invalid"), 0600); err != nil {
+		t.Fatalf("ファイルの書き込みに失敗: %v", err)
+	}
+	if _, err := loadState(path); err == nil {
+		t.Error("壊れた状態ファイルでエラーが発生しませんでした")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !plan9
+
+package certchecker
+
+import "log/syslog"
+
+// openSyslog syslogへの出力を開始する（networkとaddressが空の場合はローカルのsyslogデーモンに接続する）
+func openSyslog(network, address string, facility int, tag string) (syslogWriter, error) {
+	return syslog.Dial(network, address, syslog.Priority(facility)|syslog.LOG_INFO, tag)
+}
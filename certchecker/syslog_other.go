@@ -0,0 +1,10 @@
+//go:build windows || plan9
+
+package certchecker
+
+import "errors"
+
+// openSyslog syslogに対応していないOSではエラーを返す
+func openSyslog(network, address string, facility int, tag string) (syslogWriter, error) {
+	return nil, errors.New("このOSはsyslogへの出力に対応していません")
+}
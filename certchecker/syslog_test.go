@@ -0,0 +1,67 @@
+//go:build !windows && !plan9
+
+package certchecker
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSetupLoggerSyslog ログレベルに応じた重大度でリモートのsyslogに出力するテスト
+func TestSetupLoggerSyslog(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("UDPの待ち受けに失敗: %v", err)
+	}
+	defer conn.Close()
+
+	config := &Config{}
+	config.Logging.Syslog.Enabled = true
+	config.Logging.Syslog.Network = "udp"
+	config.Logging.Syslog.Address = conn.LocalAddr().String()
+	config.Logging.Syslog.Facility = "local0"
+
+	// ロガーのセットアップ
+	SetupLogger(config)
+	t.Cleanup(func() {
+		if logSyslog != nil {
+			logSyslog.Close()
+			logSyslog = nil
+		}
+	})
+
+	LogWarnf("example.com - 証明書の期限が近づいています")
+	Logger.Printf("%dサイトのチェックを開始します", 3)
+
+	// 優先度は「ファシリティ×8+重大度」（local0=16, warning=4, info=6）
+	lines := []struct {
+		priority string
+		message  string
+	}{
+		{"<132>", "example.com - 証明書の期限が近づいています"},
+		{"<134>", "3サイトのチェックを開始します"},
+	}
+	buf := make([]byte, 4096)
+	for _, want := range lines {
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("syslogのメッセージを受信できません: %v", err)
+		}
+		line := string(buf[:n])
+		if !strings.HasPrefix(line, want.priority) {
+			t.Errorf("優先度 期待: %s, 実際: %s", want.priority, line)
+		}
+		if !strings.Contains(line, "cert-checker[") {
+			t.Errorf("タグが含まれていません: %s", line)
+		}
+		if !strings.Contains(line, want.message) {
+			t.Errorf("メッセージに %q が含まれていません: %s", want.message, line)
+		}
+		if strings.Contains(line, "[WARN]") {
+			t.Errorf("ログレベルは重大度で送るため、メッセージには含めません: %s", line)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// teamsThemeColors ステータスに応じたMessageCardのテーマカラー
+var teamsThemeColors = map[string]string{
+	"OK":       "2EB886", // 緑
+	"WARNING":  "FFA500", // オレンジ
+	"CRITICAL": "FF0000", // 赤
+	"ERROR":    "8B0000", // 暗い赤
+}
+
+// teamsFact MessageCardのセクションに表示する項目
+type teamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// teamsSection MessageCardのセクション（サイトごとに1つ）
+type teamsSection struct {
+	ActivityTitle string      `json:"activityTitle"`
+	Facts         []teamsFact `json:"facts"`
+}
+
+// teamsMessageCard Office 365コネクターのMessageCard
+type teamsMessageCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+// SendTeamsNotification Microsoft Teamsに通知を送信
+func SendTeamsNotification(ctx context.Context, config *Config, results []CertInfo) error {
+	if !config.Teams.Enabled {
+		LogDebugf("Teams通知は無効です")
+		return nil
+	}
+
+	webhookURL := config.Teams.WebhookURL
+	if webhookURL == "" {
+		LogWarnf("Teams Webhook URLが設定されていません")
+		return nil
+	}
+
+	// 通知対象の結果をフィルタリング
+	filteredResults := filterByStatus(results, config.Teams.NotifyOn)
+
+	if len(filteredResults) == 0 {
+		LogDebugf("Teams通知対象の結果がありません")
+		return nil
+	}
+
+	sections := []teamsSection{}
+	for _, cert := range filteredResults {
+		facts := []teamsFact{
+			{Name: "URL", Value: displayAddress(cert.URL, cert.Port)},
+			{Name: config.message("status"), Value: cert.Status},
+		}
+		if cert.Status != "ERROR" {
+			facts = append(facts,
+				teamsFact{Name: config.message("days_remaining"), Value: fmt.Sprintf(config.message("days"), cert.DaysRemaining)},
+				teamsFact{Name: config.message("not_after"), Value: config.formatTime(cert.NotAfter)},
+				teamsFact{Name: config.message("issuer"), Value: cert.Issuer},
+			)
+			if cert.ErrorMessage != "" {
+				facts = append(facts, teamsFact{Name: config.message("warning"), Value: cert.ErrorMessage})
+			}
+		} else {
+			facts = append(facts, teamsFact{Name: config.message("error"), Value: cert.ErrorMessage})
+		}
+
+		sections = append(sections, teamsSection{
+			ActivityTitle: notificationTitle(config, cert),
+			Facts:         facts,
+		})
+	}
+
+	// カードの色は通知対象の中で最も深刻なステータスに合わせる
+	themeColor, ok := teamsThemeColors[worstStatus(filteredResults)]
+	if !ok {
+		themeColor = "808080" // グレー
+	}
+
+	title := fmt.Sprintf(config.message("notification_title"), config.formatTime(time.Now()))
+	card := teamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: themeColor,
+		Summary:    title,
+		Title:      title,
+		Sections:   sections,
+	}
+
+	// JSONに変換
+	jsonData, err := json.Marshal(card)
+	if err != nil {
+		return fmt.Errorf("JSONのマーシャルに失敗: %v", err)
+	}
+
+	// Webhookに送信
+	resp, err := postJSON(ctx, notifyHTTPClient(config, config.Teams.Timeout), webhookURL, jsonData)
+	if err != nil {
+		return fmt.Errorf("Teams通知の送信に失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Teamsがエラーを返しました: %d", resp.StatusCode)
+	}
+
+	LogInfof("Teams通知を送信しました")
+	return nil
+}
@@ -0,0 +1,107 @@
+package certchecker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSendTeamsNotificationDisabled Teams通知無効時のテスト
+func TestSendTeamsNotificationDisabled(t *testing.T) {
+	config := &Config{}
+	config.Teams.Enabled = false
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+	}
+
+	if err := SendTeamsNotification(context.Background(), config, results); err != nil {
+		t.Errorf("Teams通知無効時にエラーが発生しました: %v", err)
+	}
+}
+
+// TestSendTeamsNotification MessageCardの内容のテスト
+func TestSendTeamsNotification(t *testing.T) {
+	var received []teamsMessageCard
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var card teamsMessageCard
+		if err := json.NewDecoder(r.Body).Decode(&card); err != nil {
+			t.Errorf("ペイロードの解析に失敗: %v", err)
+		}
+		received = append(received, card)
+		w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	config := &Config{}
+	config.Teams.Enabled = true
+	config.Teams.WebhookURL = server.URL
+	config.Teams.NotifyOn = []string{"WARNING", "CRITICAL"}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "OK Site", URL: "ok.com", Port: 443, Status: "OK", DaysRemaining: 90},
+		{SiteName: "Warning Site", URL: "warning.com", Port: 443, Status: "WARNING", DaysRemaining: 20},
+		{SiteName: "Critical Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+	}
+
+	if err := SendTeamsNotification(context.Background(), config, results); err != nil {
+		t.Fatalf("Teams通知でエラーが発生しました: %v", err)
+	}
+	if len(received) != 1 {
+		t.Fatalf("送信回数が正しくありません。期待: 1, 実際: %d", len(received))
+	}
+
+	card := received[0]
+	if card.Type != "MessageCard" {
+		t.Errorf("@typeが正しくありません: %s", card.Type)
+	}
+	// テーマカラーは最も深刻なステータスに合わせる
+	if card.ThemeColor != teamsThemeColors["CRITICAL"] {
+		t.Errorf("テーマカラーが正しくありません。期待: %s, 実際: %s", teamsThemeColors["CRITICAL"], card.ThemeColor)
+	}
+	if len(card.Sections) != 2 {
+		t.Fatalf("セクションの数が正しくありません。期待: 2, 実際: %d", len(card.Sections))
+	}
+	if !strings.Contains(card.Sections[0].ActivityTitle, "Warning Site") || !strings.Contains(card.Sections[1].ActivityTitle, "Critical Site") {
+		t.Errorf("セクションのタイトルが正しくありません: %s, %s", card.Sections[0].ActivityTitle, card.Sections[1].ActivityTitle)
+	}
+}
+
+// TestSendTeamsNotificationErrorStatus Webhookがエラーを返した場合のテスト
+func TestSendTeamsNotificationErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	config := &Config{}
+	config.Teams.Enabled = true
+	config.Teams.WebhookURL = server.URL
+
+	var logs bytes.Buffer
+	Logger = log.New(&logs, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Error Site", URL: "error.com", Port: 443, Status: "ERROR", ErrorMessage: "Connection failed"},
+	}
+
+	err := SendTeamsNotification(context.Background(), config, results)
+	if err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("ステータスコードのエラーが返されていません: %v", err)
+	}
+	if strings.Contains(logs.String(), "Teams通知を送信しました") {
+		t.Errorf("送信に失敗したのに送信済みとしてログに記録されています: %s", logs.String())
+	}
+}
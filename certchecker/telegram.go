@@ -0,0 +1,190 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// telegramAPIBase Telegram Bot APIのベースURL
+var telegramAPIBase = "https://api.telegram.org"
+
+// telegramMaxMessageLength 1通のメッセージの最大長（UTF-16のコード単位）
+const telegramMaxMessageLength = 4096
+
+// telegramMaxErrorBody エラーに含めるレスポンスの本文の最大長（バイト）
+const telegramMaxErrorBody = 512
+
+// telegramMarkdownEscaper Markdown記法として解釈される文字をエスケープする
+var telegramMarkdownEscaper = strings.NewReplacer("_", `\_`, "*", `\*`, "`", "\\`", "[", `\[`)
+
+// telegramMessage sendMessageに送信するリクエスト
+type telegramMessage struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+// SendTelegramNotification Telegramに通知を送信
+// メッセージが長さの上限を超える場合は、サイトの区切りで複数のメッセージに分割して送信する
+// 分割したメッセージの一部の送信に失敗しても残りのメッセージの送信を続け、失敗したものをまとめて返す
+func SendTelegramNotification(ctx context.Context, config *Config, results []CertInfo) error {
+	if !config.Telegram.Enabled {
+		LogDebugf("Telegram通知は無効です")
+		return nil
+	}
+
+	if config.Telegram.BotToken == "" || config.Telegram.ChatID == "" {
+		LogWarnf("TelegramのボットトークンまたはチャットIDが設定されていません")
+		return nil
+	}
+
+	// 通知対象の結果をフィルタリング
+	filteredResults := filterByStatus(results, config.Telegram.NotifyOn)
+
+	if len(filteredResults) == 0 {
+		LogDebugf("Telegram通知対象の結果がありません")
+		return nil
+	}
+
+	header := fmt.Sprintf("*%s*\n%s\n", config.message("title"), config.formatTime(time.Now()))
+	blocks := []string{header}
+	for _, cert := range filteredResults {
+		blocks = append(blocks, formatTelegramResult(config, cert))
+	}
+
+	client := notifyHTTPClient(config, config.Telegram.Timeout)
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIBase, config.Telegram.BotToken)
+	chunks := splitTelegramMessage(blocks)
+	var errs []error
+	for i, text := range chunks {
+		jsonData, err := json.Marshal(telegramMessage{
+			ChatID:    config.Telegram.ChatID,
+			Text:      text,
+			ParseMode: "Markdown",
+		})
+		if err != nil {
+			return fmt.Errorf("JSONのマーシャルに失敗: %v", err)
+		}
+
+		if err := postTelegramMessage(ctx, client, endpoint, jsonData); err != nil {
+			errs = append(errs, fmt.Errorf("%d/%d通目: %w", i+1, len(chunks), err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	LogInfof("Telegram通知を送信しました")
+	return nil
+}
+
+// postTelegramMessage メッセージを1通送信する
+// Bot APIがエラーを返した場合は、ステータスコードと本文（エラーの説明）をエラーに含める
+func postTelegramMessage(ctx context.Context, client *http.Client, endpoint string, data []byte) error {
+	resp, err := postJSON(ctx, client, endpoint, data)
+	if err != nil {
+		// URLにはボットトークンが含まれるため、エラーメッセージにURLを含めない
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("Telegram通知の送信に失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, telegramMaxErrorBody))
+		return fmt.Errorf("Telegramがエラーを返しました: %d (%s)", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// formatTelegramResult 1サイト分の結果をMarkdown形式で作成
+func formatTelegramResult(config *Config, cert CertInfo) string {
+	var sb strings.Builder
+	if cert.Recovered {
+		sb.WriteString(fmt.Sprintf("\n✅ *%s* (%s)\n", telegramMarkdownEscaper.Replace(cert.SiteName), fmt.Sprintf(config.message("status_recovered"), cert.Status)))
+	} else {
+		sb.WriteString(fmt.Sprintf("\n🔒 *%s* (%s)\n", telegramMarkdownEscaper.Replace(cert.SiteName), cert.Status))
+	}
+	sb.WriteString(fmt.Sprintf("URL: %s\n", telegramMarkdownEscaper.Replace(displayAddress(cert.URL, cert.Port))))
+	if cert.Status != "ERROR" {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", config.message("days_remaining"), fmt.Sprintf(config.message("days"), cert.DaysRemaining)))
+		sb.WriteString(fmt.Sprintf("%s: %s\n", config.message("not_after"), config.formatTime(cert.NotAfter)))
+		if cert.ErrorMessage != "" {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", config.message("warning"), telegramMarkdownEscaper.Replace(cert.ErrorMessage)))
+		}
+	} else {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", config.message("error"), telegramMarkdownEscaper.Replace(cert.ErrorMessage)))
+	}
+	return sb.String()
+}
+
+// splitTelegramMessage ブロックを上限の長さに収まるようにまとめて、送信するメッセージの一覧を返す
+// 1つのブロックだけで上限を超える場合は、そのブロックを途中で分割する
+func splitTelegramMessage(blocks []string) []string {
+	var messages []string
+	var current strings.Builder
+	currentLength := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			messages = append(messages, current.String())
+			current.Reset()
+			currentLength = 0
+		}
+	}
+
+	for _, block := range blocks {
+		length := telegramLength(block)
+		if currentLength+length > telegramMaxMessageLength {
+			flush()
+		}
+		for length > telegramMaxMessageLength {
+			head, rest := splitAtTelegramLength(block, telegramMaxMessageLength)
+			messages = append(messages, head)
+			block, length = rest, telegramLength(rest)
+		}
+		current.WriteString(block)
+		currentLength += length
+	}
+	flush()
+
+	return messages
+}
+
+// telegramLength Telegramが数える文字列の長さ（UTF-16のコード単位）
+func telegramLength(s string) int {
+	length := 0
+	for _, r := range s {
+		length += utf16Len(r)
+	}
+	return length
+}
+
+// utf16Len 文字をUTF-16で表現したときのコード単位の数（基本多言語面外の文字はサロゲートペアで2）
+func utf16Len(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// splitAtTelegramLength 文字の途中で切らないよう、指定した長さ以内の先頭部分と残りに分割する
+func splitAtTelegramLength(s string, limit int) (string, string) {
+	length := 0
+	for i, r := range s {
+		length += utf16Len(r)
+		if length > limit {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
@@ -0,0 +1,223 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTelegramServer 受信したメッセージを記録するモックのBot APIサーバーを起動する
+func newTelegramServer(t *testing.T, messages *[]telegramMessage) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottest-token/sendMessage" {
+			t.Errorf("リクエストのパスが正しくありません: %s", r.URL.Path)
+		}
+		var msg telegramMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("リクエストの解析に失敗: %v", err)
+		}
+		*messages = append(*messages, msg)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	original := telegramAPIBase
+	telegramAPIBase = server.URL
+	t.Cleanup(func() { telegramAPIBase = original })
+}
+
+// TestSendTelegramNotificationDisabled Telegram通知無効時のテスト
+func TestSendTelegramNotificationDisabled(t *testing.T) {
+	var messages []telegramMessage
+	newTelegramServer(t, &messages)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "Test Site", URL: "test.com", Port: 443, Status: "CRITICAL", DaysRemaining: 5},
+	}
+
+	config := &Config{}
+	config.Telegram.Enabled = false
+	config.Telegram.BotToken = "test-token"
+	config.Telegram.ChatID = "12345"
+	if err := SendTelegramNotification(context.Background(), config, results); err != nil {
+		t.Errorf("Telegram通知無効時にエラーが発生しました: %v", err)
+	}
+
+	// トークン未設定の場合も送信しない
+	config.Telegram.Enabled = true
+	config.Telegram.BotToken = ""
+	if err := SendTelegramNotification(context.Background(), config, results); err != nil {
+		t.Errorf("トークン未設定時にエラーが発生しました: %v", err)
+	}
+
+	if len(messages) != 0 {
+		t.Errorf("送信されるべきでないメッセージが送信されました: %d件", len(messages))
+	}
+}
+
+// TestSendTelegramNotificationFiltering 通知フィルタリングのテスト
+func TestSendTelegramNotificationFiltering(t *testing.T) {
+	var messages []telegramMessage
+	newTelegramServer(t, &messages)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Telegram.Enabled = true
+	config.Telegram.BotToken = "test-token"
+	config.Telegram.ChatID = "12345"
+	config.Telegram.NotifyOn = []string{"CRITICAL"}
+
+	results := []CertInfo{
+		{SiteName: "OK Site", URL: "ok.com", Port: 443, Status: "OK", DaysRemaining: 90},
+		{SiteName: "Critical_Site", URL: "critical.com", Port: 443, Status: "CRITICAL", DaysRemaining: 3},
+	}
+
+	if err := SendTelegramNotification(context.Background(), config, results); err != nil {
+		t.Fatalf("Telegram通知でエラーが発生しました: %v", err)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("送信回数が正しくありません。期待: 1, 実際: %d", len(messages))
+	}
+
+	msg := messages[0]
+	if msg.ChatID != "12345" || msg.ParseMode != "Markdown" {
+		t.Errorf("リクエストが正しくありません: %+v", msg)
+	}
+	if strings.Contains(msg.Text, "OK Site") {
+		t.Error("通知対象外のサイトが含まれています")
+	}
+	// Markdownの記号はエスケープされる
+	if !strings.Contains(msg.Text, `Critical\_Site`) {
+		t.Errorf("サイト名が正しくエスケープされていません: %s", msg.Text)
+	}
+}
+
+// TestSendTelegramNotificationChunking 長いメッセージの分割送信のテスト
+func TestSendTelegramNotificationChunking(t *testing.T) {
+	var messages []telegramMessage
+	newTelegramServer(t, &messages)
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Telegram.Enabled = true
+	config.Telegram.BotToken = "test-token"
+	config.Telegram.ChatID = "12345"
+
+	var results []CertInfo
+	for i := 0; i < 100; i++ {
+		results = append(results, CertInfo{
+			SiteName:      fmt.Sprintf("サイト%03d", i),
+			URL:           fmt.Sprintf("site%03d.example.com", i),
+			Port:          443,
+			Status:        "WARNING",
+			DaysRemaining: 20,
+		})
+	}
+
+	if err := SendTelegramNotification(context.Background(), config, results); err != nil {
+		t.Fatalf("Telegram通知でエラーが発生しました: %v", err)
+	}
+	if len(messages) < 2 {
+		t.Fatalf("メッセージが分割されていません: %d件", len(messages))
+	}
+
+	var all strings.Builder
+	for i, msg := range messages {
+		if n := telegramLength(msg.Text); n > telegramMaxMessageLength {
+			t.Errorf("%d通目のメッセージが長すぎます: %d", i+1, n)
+		}
+		all.WriteString(msg.Text)
+	}
+	// 分割されてもすべてのサイトが1回ずつ含まれる
+	for _, result := range results {
+		if n := strings.Count(all.String(), result.SiteName+"*"); n != 1 {
+			t.Errorf("%s の出現回数が正しくありません: %d", result.SiteName, n)
+		}
+	}
+}
+
+// TestSendTelegramNotificationError Bot APIがエラーを返した場合に、残りのメッセージも送信してエラーを返すことのテスト
+func TestSendTelegramNotificationError(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// 1通目だけ失敗させる
+		if requests == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	original := telegramAPIBase
+	telegramAPIBase = server.URL
+	defer func() { telegramAPIBase = original }()
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	config := &Config{}
+	config.Telegram.Enabled = true
+	config.Telegram.BotToken = "test-token"
+	config.Telegram.ChatID = "12345"
+
+	var results []CertInfo
+	for i := 0; i < 100; i++ {
+		results = append(results, CertInfo{
+			SiteName:      fmt.Sprintf("サイト%03d", i),
+			URL:           fmt.Sprintf("site%03d.example.com", i),
+			Port:          443,
+			Status:        "WARNING",
+			DaysRemaining: 20,
+		})
+	}
+
+	err := SendTelegramNotification(context.Background(), config, results)
+	if err == nil {
+		t.Fatal("Bot APIのエラーが返されませんでした")
+	}
+	for _, expected := range []string{"1/", "400", "can't parse entities"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("エラーに %q が含まれていません: %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "test-token") {
+		t.Errorf("エラーにボットトークンが含まれています: %v", err)
+	}
+	if requests < 2 {
+		t.Errorf("失敗した後の残りのメッセージが送信されていません: %d通", requests)
+	}
+}
+
+// TestSplitTelegramMessageLongBlock 1つのブロックが上限を超える場合の分割のテスト
+func TestSplitTelegramMessageLongBlock(t *testing.T) {
+	block := strings.Repeat("証", telegramMaxMessageLength+10)
+	messages := splitTelegramMessage([]string{"header\n", block})
+
+	total := 0
+	for _, msg := range messages {
+		if n := telegramLength(msg); n > telegramMaxMessageLength {
+			t.Errorf("メッセージが長すぎます: %d", n)
+		}
+		total += telegramLength(msg)
+	}
+	if total != len("header\n")+telegramMaxMessageLength+10 {
+		t.Errorf("分割後の合計の長さが正しくありません: %d", total)
+	}
+}
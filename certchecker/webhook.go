@@ -0,0 +1,107 @@
+package certchecker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// webhookTemplateData Webhookの本文テンプレートに渡すデータ
+type webhookTemplateData struct {
+	CheckTime string
+	Summary   jsonSummary
+	Results   []CertInfo
+}
+
+// webhookTemplateFuncs 本文テンプレートで使用できる関数
+var webhookTemplateFuncs = template.FuncMap{
+	// json 値をJSONとしてエンコードする（文字列のエスケープに使用）
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseWebhookTemplate webhook.bodyの本文テンプレートを解析する（設定の読み込み時に1回だけ行う）
+func parseWebhookTemplate(body string) (*template.Template, error) {
+	return template.New("webhook").Funcs(webhookTemplateFuncs).Parse(body)
+}
+
+// renderWebhookBody 設定の読み込み時に解析した本文テンプレートを展開する
+// テンプレートが指定されていない場合はJSONレポートを本文とする（チェック日時はreport.timezoneのタイムゾーン）
+func renderWebhookBody(config *Config, results []CertInfo) (string, error) {
+	if config.webhookTemplate == nil {
+		return GenerateJSONReport(config, results), nil
+	}
+
+	var buf bytes.Buffer
+	data := webhookTemplateData{
+		CheckTime: time.Now().In(config.reportLocation()).Format(time.RFC3339),
+		Summary:   summarizeResults(results),
+		Results:   results,
+	}
+	if err := config.webhookTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("テンプレートの展開に失敗: %v", err)
+	}
+	return buf.String(), nil
+}
+
+// SendWebhookNotification 任意のWebhookに通知を送信
+func SendWebhookNotification(ctx context.Context, config *Config, results []CertInfo) error {
+	if !config.Webhook.Enabled {
+		LogDebugf("Webhook通知は無効です")
+		return nil
+	}
+
+	if config.Webhook.URL == "" {
+		LogWarnf("Webhook URLが設定されていません")
+		return nil
+	}
+
+	// 通知対象の結果をフィルタリング
+	filteredResults := filterByStatus(results, config.Webhook.NotifyOn)
+
+	if len(filteredResults) == 0 {
+		LogDebugf("Webhook通知対象の結果がありません")
+		return nil
+	}
+
+	body, err := renderWebhookBody(config, filteredResults)
+	if err != nil {
+		return err
+	}
+
+	method := strings.ToUpper(config.Webhook.Method)
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, config.Webhook.URL, strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("リクエストの作成に失敗: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range config.Webhook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := notifyHTTPClient(config, config.Webhook.Timeout).Do(req)
+	if err != nil {
+		return fmt.Errorf("Webhook通知の送信に失敗: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhookがエラーを返しました: %d", resp.StatusCode)
+	}
+
+	LogInfof("Webhook通知を送信しました")
+	return nil
+}
@@ -0,0 +1,162 @@
+package certchecker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSendWebhookNotification テンプレートを使用したWebhook通知のテスト
+func TestSendWebhookNotification(t *testing.T) {
+	var (
+		method      string
+		contentType string
+		token       string
+		body        string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		token = r.Header.Get("X-Token")
+		body = string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := webhookTestConfig(t, `{{.Summary.Total}}件:{{range .Results}} {{.SiteName}}={{.Status}}{{end}}`)
+	config.Webhook.Enabled = true
+	config.Webhook.URL = server.URL
+	config.Webhook.Method = "put"
+	config.Webhook.Headers = map[string]string{
+		"Content-Type": "text/plain",
+		"X-Token":      "secret",
+	}
+	config.Webhook.NotifyOn = []string{"WARNING", "CRITICAL"}
+
+	// ロガーのセットアップ
+	Logger = log.New(io.Discard, "", log.LstdFlags)
+
+	results := []CertInfo{
+		{SiteName: "OK Site", Status: "OK"},
+		{SiteName: "Warning Site", Status: "WARNING"},
+		{SiteName: "Critical Site", Status: "CRITICAL"},
+	}
+
+	if err := SendWebhookNotification(context.Background(), config, results); err != nil {
+		t.Fatalf("Webhook通知でエラーが発生しました: %v", err)
+	}
+
+	if method != http.MethodPut {
+		t.Errorf("メソッドが正しくありません。期待: PUT, 実際: %s", method)
+	}
+	if contentType != "text/plain" || token != "secret" {
+		t.Errorf("ヘッダーが正しくありません: Content-Type=%s, X-Token=%s", contentType, token)
+	}
+	expected := "2件: Warning Site=WARNING Critical Site=CRITICAL"
+	if body != expected {
+		t.Errorf("本文が正しくありません。期待: %s, 実際: %s", expected, body)
+	}
+}
+
+// webhookTestConfig 本文テンプレートを解析した設定を作成する（LoadConfigと同じ処理）
+func webhookTestConfig(t *testing.T, body string) *Config {
+	t.Helper()
+	config := &Config{}
+	config.Webhook.Body = body
+	tmpl, err := parseWebhookTemplate(body)
+	if err != nil {
+		t.Fatalf("テンプレートの解析に失敗: %v", err)
+	}
+	config.webhookTemplate = tmpl
+	return config
+}
+
+// TestRenderWebhookBody 本文テンプレートの展開のテスト
+func TestRenderWebhookBody(t *testing.T) {
+	results := []CertInfo{{SiteName: `Quoted "Site"`, Status: "ERROR"}}
+
+	// json関数で文字列をエスケープできる
+	body, err := renderWebhookBody(webhookTestConfig(t, `{"text": {{json (index .Results 0).SiteName}}, "status": "{{lower (index .Results 0).Status}}"}`), results)
+	if err != nil {
+		t.Fatalf("テンプレートの展開に失敗: %v", err)
+	}
+	var parsed map[string]string
+	if err := json.Unmarshal([]byte(body), &parsed); err != nil {
+		t.Fatalf("展開結果がJSONとして解析できません: %v\n%s", err, body)
+	}
+	if parsed["text"] != `Quoted "Site"` || parsed["status"] != "error" {
+		t.Errorf("展開結果が正しくありません: %v", parsed)
+	}
+
+	// テンプレート未指定の場合はJSONレポート
+	body, err = renderWebhookBody(&Config{}, results)
+	if err != nil {
+		t.Fatalf("テンプレートの展開に失敗: %v", err)
+	}
+	var report struct {
+		Summary jsonSummary `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(body), &report); err != nil || report.Summary.Error != 1 {
+		t.Errorf("JSONレポートが正しくありません: %v\n%s", err, body)
+	}
+
+	// チェック日時はreport.timezoneのタイムゾーン
+	if location, err := time.LoadLocation("America/New_York"); err == nil {
+		config := webhookTestConfig(t, "{{.CheckTime}}")
+		config.location = location
+		body, err := renderWebhookBody(config, results)
+		if err != nil {
+			t.Fatalf("テンプレートの展開に失敗: %v", err)
+		}
+		checkTime, err := time.Parse(time.RFC3339, body)
+		if err != nil {
+			t.Fatalf("チェック日時がRFC3339形式ではありません: %s", body)
+		}
+		if _, offset := checkTime.Zone(); offset != -5*3600 && offset != -4*3600 {
+			t.Errorf("チェック日時がreport.timezoneのタイムゾーンではありません: %s", body)
+		}
+	}
+
+	// 展開できないテンプレートはエラー
+	if _, err := renderWebhookBody(webhookTestConfig(t, "{{.Unknown}}"), results); err == nil {
+		t.Error("展開できないテンプレートでエラーが発生しませんでした")
+	}
+}
+
+// TestLoadConfigWebhookBody webhook.bodyを設定の読み込み時に解析し、不正なテンプレートは起動時にエラーとするテスト
+func TestLoadConfigWebhookBody(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(body string) {
+		yaml := "webhook:\n  enabled: true\n  url: https://example.com/hook\n  body: '" + body + "'\n"
+		if err := os.WriteFile(configPath, []byte(yaml), 0600); err != nil {
+			t.Fatalf("設定ファイルの書き込みに失敗: %v", err)
+		}
+	}
+
+	write("{{.Summary.Total}}件")
+	config, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("設定ファイルの読み込みに失敗: %v", err)
+	}
+	if config.webhookTemplate == nil {
+		t.Fatal("本文のテンプレートが解析されていません")
+	}
+	body, err := renderWebhookBody(config, []CertInfo{{SiteName: "Example", Status: "OK"}})
+	if err != nil || body != "1件" {
+		t.Errorf("本文が正しくありません。期待: 1件, 実際: %s (%v)", body, err)
+	}
+
+	write("{{.Unknown")
+	if _, err := LoadConfig(configPath); err == nil || !strings.Contains(err.Error(), "webhook.body") {
+		t.Errorf("不正なテンプレートでwebhook.bodyのエラーが発生しませんでした: %v", err)
+	}
+}